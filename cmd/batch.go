@@ -2,9 +2,9 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
-	"github.com/sgaunet/mdtohtml/pkg/converter"
 	"github.com/sgaunet/mdtohtml/pkg/processor"
 )
 
@@ -12,6 +12,11 @@ var (
 	outputDir string
 	pattern   string
 	recursive bool
+	jobs      int
+	keepGoing bool
+
+	batchRewriteLinks bool
+	copyAssets        bool
 )
 
 var batchCmd = &cobra.Command{
@@ -23,7 +28,8 @@ By default, processes all *.md files in the specified directory.`,
 	RunE: batchConvert,
 	Example: `  mdtohtml batch ./docs --out-dir ./html
   mdtohtml batch ./docs --pattern "*.markdown" --out-dir ./public
-  mdtohtml batch ./docs --recursive --out-dir ./output`,
+  mdtohtml batch ./docs --recursive --out-dir ./output
+  mdtohtml batch ./docs --recursive --format=pdf --jobs 8 --out-dir ./pdf`,
 }
 
 func init() {
@@ -32,6 +38,11 @@ func init() {
 	batchCmd.Flags().StringVarP(&outputDir, "out-dir", "o", ".", "Output directory for HTML files")
 	batchCmd.Flags().StringVarP(&pattern, "pattern", "p", "*.md", "File pattern to match")
 	batchCmd.Flags().BoolVarP(&recursive, "recursive", "r", false, "Process directories recursively")
+	batchCmd.Flags().IntVarP(&jobs, "jobs", "j", 0, "Number of files to convert in parallel (0 = GOMAXPROCS)")
+	batchCmd.Flags().BoolVarP(&keepGoing, "keep-going", "k", false,
+		"Convert every file even if some fail, then report all failures")
+	batchCmd.Flags().BoolVar(&copyAssets, "copy-assets", true,
+		"Copy local images and other files referenced by the documents into the output directory")
 	batchCmd.Flags().BoolVar(&smartypants, "smartypants", true,
 		`Convert quotes to curly quotes, -- to en/em-dash, ... to ellipsis`)
 	batchCmd.Flags().BoolVar(&latexdashes, "latexdashes", true,
@@ -45,57 +56,77 @@ func init() {
 	batchCmd.Flags().BoolVar(&noCSS, "no-css", false, "Disable CSS injection entirely")
 	batchCmd.Flags().StringVar(&outputFormat, "format", formatHTML,
 		`Output format: "html" or "pdf"`)
-	batchCmd.Flags().StringVar(&pageSize, "page-size", "A4",
-		`PDF page size when --format=pdf: A4, Letter, Legal, A3, A5, Tabloid`)
-	batchCmd.Flags().StringVar(&marginFlag, "margin", defaultMarginFlag,
-		`PDF page margin (units: pt, in, cm, mm; bare numbers = pt)`)
+	addPDFFlags(batchCmd)
+	addHighlightFlags(batchCmd)
+	addTOCFlags(batchCmd)
+	addSelfContainedFlags(batchCmd)
+	addRewriteLinksFlag(batchCmd, &batchRewriteLinks, true)
+	addWatchFlag(batchCmd)
 }
 
-func batchConvert(_ *cobra.Command, args []string) error {
+func batchConvert(cmd *cobra.Command, args []string) error {
 	inputDir := args[0]
 
 	if err := validateInputDir(inputDir); err != nil {
 		return err
 	}
+	if jobs < 0 {
+		return fmt.Errorf("%w: %d", errInvalidJobs, jobs)
+	}
 
-	source, additional, err := resolveCSSOptions(
-		cssFile, cssURL, additionalCSSFile, noCSS,
-	)
+	format, err := resolveFormat(outputFormat, "")
 	if err != nil {
 		return err
 	}
 
-	// Create converter with options
-	options := converter.Options{
-		SmartPunctuation: smartypants,
-		LaTeXDashes:      latexdashes,
-		Fractions:        fractions,
-		SafeMode:         safeMode,
-		CSSSource:        source,
-		AdditionalCSS:    additional,
-		NoCSS:            noCSS,
+	processOptions := processor.ProcessOptions{
+		OutputDir:   outputDir,
+		Pattern:     pattern,
+		Recursive:   recursive,
+		OutputExt:   extForFormat(format),
+		Concurrency: jobs,
+		KeepGoing:   keepGoing,
+		CopyAssets:  copyAssets,
 	}
 
-	format, err := resolveFormat(outputFormat, "")
-	if err != nil {
-		return err
+	if watchMode {
+		return watchBatch(cmd.Context(), inputDir, processOptions, func() (*processor.FileProcessor, error) {
+			return newBatchProcessor(format, inputDir, processOptions)
+		})
 	}
 
-	conv, err := buildConverter(options, format, pageSize, marginFlag)
+	proc, err := newBatchProcessor(format, inputDir, processOptions)
 	if err != nil {
 		return err
 	}
-	proc := processor.NewFileProcessor(conv)
-
-	processOptions := processor.ProcessOptions{
-		OutputDir: outputDir,
-		Pattern:   pattern,
-		Recursive: recursive,
-		OutputExt: extForFormat(format),
-	}
-
 	if err := proc.ProcessDirectory(inputDir, processOptions); err != nil {
 		return fmt.Errorf("batch processing failed for directory '%s': %w", inputDir, err)
 	}
 	return nil
 }
+
+// newBatchProcessor resolves the CSS flags and returns a FileProcessor whose
+// converter produces format. Links are only rewritten when they point at a
+// file that the batch run over inputDir with processOptions converts.
+func newBatchProcessor(format, inputDir string, processOptions processor.ProcessOptions) (*processor.FileProcessor, error) {
+	source, additional, err := resolveCSSOptions(
+		cssFile, cssURL, additionalCSSFile, noCSS,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	options := converterOptions(source, additional)
+	if batchRewriteLinks {
+		options.MarkdownLinkExt = extForFormat(format)
+		options.MarkdownLinkTarget = func(path string) bool {
+			info, err := os.Stat(path)
+			return err == nil && info.Mode().IsRegular() && processOptions.Matches(inputDir, path)
+		}
+	}
+	conv, err := buildConverter(options, format)
+	if err != nil {
+		return nil, err
+	}
+	return processor.NewFileProcessor(conv), nil
+}
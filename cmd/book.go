@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sgaunet/mdtohtml/pkg/pdf"
+)
+
+var (
+	bookOutput  string
+	bookSummary string
+)
+
+var bookCmd = &cobra.Command{
+	Use:   "book [chapter.md...]",
+	Short: "Combine Markdown files into a single PDF book",
+	Long: `Combine Markdown files into a single PDF book, one chapter per file.
+Chapters are taken in the order given, or from a SUMMARY.md-style outline
+with --summary. Each chapter starts on a new page with a bookmark holding its
+headings, links between the chapter files jump to the linked headings, and
+relative images resolve against the directory of their chapter.`,
+	RunE: book,
+	Example: `  mdtohtml book intro.md install.md usage.md -o manual.pdf
+  mdtohtml book --summary docs/SUMMARY.md -o manual.pdf --page-size Letter`,
+}
+
+func init() {
+	rootCmd.AddCommand(bookCmd)
+
+	bookCmd.Flags().StringVarP(&bookOutput, "output", "o", "", "Output PDF file (required)")
+	bookCmd.Flags().StringVar(&bookSummary, "summary", "",
+		"Markdown outline whose links, nested in lists, list the chapters (instead of chapter arguments)")
+	_ = bookCmd.MarkFlagRequired("output")
+	bookCmd.Flags().BoolVar(&smartypants, "smartypants", true,
+		`Convert quotes to curly quotes, -- to en/em-dash, ... to ellipsis`)
+	bookCmd.Flags().BoolVar(&latexdashes, "latexdashes", true,
+		`LaTeX-style dashes: --- for em-dash, -- for en-dash (requires --smartypants)`)
+	bookCmd.Flags().BoolVar(&fractions, "fractions", true,
+		`Convert fractions: 1/2 to ½, 1/4 to ¼, 3/4 to ¾`)
+	bookCmd.Flags().BoolVar(&safeMode, "safe-mode", false, "Disable raw HTML pass-through to prevent XSS")
+	bookCmd.Flags().StringVar(&cssFile, "css-file", "", "Path to a CSS file to use instead of the default GitHub CSS")
+	bookCmd.Flags().StringVar(&cssURL, "css-url", "", "URL to fetch CSS from instead of the default GitHub CSS")
+	bookCmd.Flags().StringVar(&additionalCSSFile, "additional-css", "", "Path to a CSS file to append to the default CSS")
+	bookCmd.Flags().BoolVar(&noCSS, "no-css", false, "Disable CSS injection entirely")
+	addPDFFlags(bookCmd)
+	addHighlightFlags(bookCmd)
+	addTOCFlags(bookCmd)
+}
+
+func book(_ *cobra.Command, args []string) error {
+	chapters, err := bookChapters(bookSummary, args)
+	if err != nil {
+		return err
+	}
+
+	source, additional, err := resolveCSSOptions(
+		cssFile, cssURL, additionalCSSFile, noCSS,
+	)
+	if err != nil {
+		return err
+	}
+	options := converterOptions(source, additional)
+	if err := validateConverterOptions(options); err != nil {
+		return err
+	}
+	conv, err := newPDFConverter(options)
+	if err != nil {
+		return err
+	}
+	if err := conv.ConvertBook(chapters, bookOutput); err != nil {
+		return fmt.Errorf("book conversion failed: %w", err)
+	}
+	return nil
+}
+
+// bookChapters returns the chapters listed by the outline at summary, or
+// the chapter files of args when summary is empty.
+func bookChapters(summary string, args []string) ([]pdf.Chapter, error) {
+	switch {
+	case summary != "" && len(args) > 0:
+		return nil, errSummaryWithChapters
+	case summary != "":
+		if err := validateInputFile(summary); err != nil {
+			return nil, err
+		}
+		chapters, err := pdf.ParseSummary(summary)
+		if err != nil {
+			return nil, fmt.Errorf("reading book outline: %w", err)
+		}
+		return chapters, nil
+	case len(args) == 0:
+		return nil, errNoChapters
+	}
+	chapters := make([]pdf.Chapter, len(args))
+	for i, path := range args {
+		if err := validateInputFile(path); err != nil {
+			return nil, err
+		}
+		chapters[i] = pdf.Chapter{Path: path}
+	}
+	return chapters, nil
+}
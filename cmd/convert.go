@@ -2,35 +2,215 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
 
 	"github.com/sgaunet/mdtohtml/pkg/converter"
 	"github.com/sgaunet/mdtohtml/pkg/pdf"
 )
 
-// cssOptions groups the CSS-related flags for runConversion.
-type cssOptions struct {
-	source     string
-	additional string
-	noCSS      bool
+// addHighlightFlags registers the syntax highlighting flags on cmd.
+func addHighlightFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&highlightStyle, "highlight-style", "",
+		`Syntax highlighting style for fenced code blocks, e.g. "github", "monokai" (empty = disabled)`)
+	cmd.Flags().BoolVar(&highlightClasses, "highlight-classes", false,
+		"Emit CSS classes plus a stylesheet instead of inline styles for highlighted code (HTML only)")
+	cmd.Flags().BoolVar(&lineNumbers, "line-numbers", false,
+		"Show line numbers in highlighted code blocks")
+}
+
+// addPDFFlags registers the PDF page layout flags on cmd.
+func addPDFFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&pageSize, "page-size", pdf.PageSizeA4,
+		`PDF page size when --format=pdf: A0-A10, B0-B10, C0-C10, Letter, Legal, Tabloid, Ledger, Executive, `+
+			`Statement, or WIDTHxHEIGHT such as 6inx9in (units: pt, in, cm, mm)`)
+	cmd.Flags().StringVar(&marginFlag, "margin", defaultMarginFlag,
+		`PDF page margins: one to four values ordered as in CSS, e.g. "1in" or "1in 0.75in" `+
+			`(units: pt, in, cm, mm; bare numbers = pt)`)
+	cmd.Flags().StringVar(&orientation, "orientation", "",
+		`PDF page orientation: portrait or landscape (default: as given by --page-size)`)
+	cmd.Flags().StringVar(&headerTemplate, "header", "",
+		`PDF running header: a template with {title}, {page}, {pages}, {date} and {filename}; `+
+			`"|" separates left, center and right slots, e.g. "{title}||Page {page} of {pages}"`)
+	cmd.Flags().StringVar(&footerTemplate, "footer", "",
+		`PDF running footer, written like --header`)
+	cmd.Flags().BoolVar(&headerSkipFirst, "header-footer-skip-first", false,
+		"Leave the first PDF page without header and footer")
+	cmd.Flags().StringVar(&pdfAuthor, "pdf-author", "",
+		"PDF document author (default: the front matter author)")
+	cmd.Flags().StringVar(&pdfSubject, "pdf-subject", "",
+		"PDF document subject (default: the front matter subject or description)")
+	cmd.Flags().StringVar(&pdfKeywords, "pdf-keywords", "",
+		`PDF document keywords, e.g. "audit, 2024" (default: the front matter keywords)`)
+	cmd.Flags().BoolVar(&coverEnabled, "cover", false,
+		"Start the PDF with a cover page (implied by the other --cover-* flags)")
+	cmd.Flags().StringVar(&coverTitle, "cover-title", "", "PDF cover page title (default: the document title)")
+	cmd.Flags().StringVar(&coverSubtitle, "cover-subtitle", "", "PDF cover page subtitle")
+	cmd.Flags().StringVar(&coverAuthor, "cover-author", "",
+		"PDF cover page author (default: --pdf-author or the front matter author)")
+	cmd.Flags().StringVar(&coverDate, "cover-date", "",
+		`PDF cover page date (default: the creation date, e.g. "January 2, 2006")`)
+	cmd.Flags().StringVar(&coverLogo, "cover-logo", "", "PNG or JPEG image drawn above the PDF cover page title")
+	cmd.Flags().BoolVar(&pdfContents, "pdf-toc", false,
+		"Add a PDF contents page listing the headings with their page numbers, after the cover page")
+	cmd.Flags().IntVar(&pdfContentsDepth, "pdf-toc-depth", pdf.DefaultContentsDepth,
+		"Deepest heading level listed on the PDF contents page")
+	cmd.Flags().BoolVar(&pageBreakH1, "page-break-before-h1", false,
+		`Start every H1 on a new page in the PDF and printed HTML (breaks can also be forced with \pagebreak lines)`)
+	cmd.Flags().StringArrayVar(&pdfFonts, "pdf-font", nil,
+		`TrueType/OpenType files of the PDF text font: REGULAR[,BOLD[,ITALIC[,BOLDITALIC]]]; `+
+			`repeat for fallbacks drawing the characters missing from the previous fonts`)
+	cmd.Flags().StringArrayVar(&pdfMonoFonts, "pdf-mono-font", nil,
+		`TrueType/OpenType files of the PDF code font, written and repeated like --pdf-font`)
+	cmd.Flags().StringVar(&watermarkText, "watermark", "",
+		`Text drawn on every PDF page, e.g. "DRAFT" (default: the front matter status unless final, published, approved or released)`)
+	cmd.Flags().StringVar(&watermarkImage, "watermark-image", "", "PNG or JPEG image drawn on every PDF page")
+	cmd.Flags().Float64Var(&watermarkOpacity, "watermark-opacity", pdf.DefaultWatermarkOpacity,
+		"Opacity of the PDF watermark, from 0 to 1")
+	cmd.Flags().Float64Var(&watermarkAngle, "watermark-angle", pdf.DefaultWatermarkAngle,
+		"Counterclockwise angle of the PDF watermark text in degrees")
+	cmd.Flags().StringVar(&watermarkColor, "watermark-color", pdf.DefaultWatermarkColor,
+		"Color of the PDF watermark text, #RGB or #RRGGBB")
+	cmd.Flags().Float64Var(&watermarkSize, "watermark-size", pdf.DefaultWatermarkFontSize,
+		"Font size of the PDF watermark text in points, reduced to fit the page")
+	cmd.Flags().BoolVar(&watermarkOver, "watermark-over", false,
+		"Draw the PDF watermark over the content instead of behind it")
+}
+
+// addTOCFlags registers the table of contents flags on cmd.
+func addTOCFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&tocEnabled, "toc", false,
+		"Insert a table of contents at the top of the document or at a [TOC] / <!-- toc --> placeholder")
+	cmd.Flags().IntVar(&tocMinDepth, "toc-min-depth", 1, "Shallowest heading level listed in the table of contents")
+	cmd.Flags().IntVar(&tocMaxDepth, "toc-max-depth", 6, //nolint:mnd // H6 is the deepest heading level
+		"Deepest heading level listed in the table of contents")
+}
+
+// addSelfContainedFlags registers the flags that embed local resources into
+// HTML output on cmd.
+func addSelfContainedFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&selfContained, "self-contained", false,
+		"Embed local images as data: URIs so the HTML file works on its own (HTML only)")
+	cmd.Flags().BoolVar(&inlineStylesheets, "inline-stylesheets", false,
+		`Replace <link rel="stylesheet"> elements to local CSS files with their content (HTML only)`)
 }
 
-func runConversion(
-	inputFilePath, outputFilePath string,
-	smartypants, latexdashes, fractions, safeMode bool,
-	css cssOptions,
-	format, pageSize, margin string,
-) error {
-	options := converter.Options{
+// addRewriteLinksFlag registers the --rewrite-links flag on cmd, bound to
+// target. batch enables it by default, convert does not.
+func addRewriteLinksFlag(cmd *cobra.Command, target *bool, defaultValue bool) {
+	cmd.Flags().BoolVar(target, "rewrite-links", defaultValue,
+		"Point relative links to .md files at the converted .html/.pdf files")
+}
+
+// tocOptions returns the table of contents options selected by the flags.
+func tocOptions() converter.TOCOptions {
+	return converter.TOCOptions{Enabled: tocEnabled, MinDepth: tocMinDepth, MaxDepth: tocMaxDepth}
+}
+
+// converterOptions returns the converter options selected by the
+// typography, safe-mode, highlighting, table of contents, page break and
+// embedding flags, with the already resolved CSS texts.
+func converterOptions(cssSource, additionalCSS string) converter.Options {
+	return converter.Options{
 		SmartPunctuation: smartypants,
 		LaTeXDashes:      latexdashes,
 		Fractions:        fractions,
 		SafeMode:         safeMode,
-		CSSSource:        css.source,
-		AdditionalCSS:    css.additional,
-		NoCSS:            css.noCSS,
+		CSSSource:        cssSource,
+		AdditionalCSS:    additionalCSS,
+		NoCSS:            noCSS,
+
+		HighlightStyle:       highlightStyle,
+		HighlightCSSClasses:  highlightClasses,
+		HighlightLineNumbers: lineNumbers,
+
+		TOC:               tocOptions(),
+		PageBreakBeforeH1: pageBreakH1,
+
+		SelfContained:     selfContained,
+		InlineStylesheets: inlineStylesheets,
+	}
+}
+
+// pdfOptions returns the PDF options selected by the page layout, header,
+// metadata, cover and contents flags and by SOURCE_DATE_EPOCH.
+func pdfOptions() (pdf.Options, error) {
+	margins, err := pdf.ParseMargins(marginFlag)
+	if err != nil {
+		return pdf.Options{}, err
+	}
+	header, err := pdf.ParseRunningText(headerTemplate)
+	if err != nil {
+		return pdf.Options{}, err
+	}
+	footer, err := pdf.ParseRunningText(footerTemplate)
+	if err != nil {
+		return pdf.Options{}, err
+	}
+	created, err := pdf.SourceDateEpoch(os.Getenv(pdf.SourceDateEpochEnv))
+	if err != nil {
+		return pdf.Options{}, err
+	}
+	body, err := parseFonts(pdfFonts)
+	if err != nil {
+		return pdf.Options{}, err
+	}
+	mono, err := parseFonts(pdfMonoFonts)
+	if err != nil {
+		return pdf.Options{}, err
 	}
+	return pdf.Options{
+		PageSize:              pageSize,
+		Margins:               margins,
+		Orientation:           orientation,
+		Header:                header,
+		Footer:                footer,
+		HeaderFooterSkipFirst: headerSkipFirst,
+		Author:                pdfAuthor,
+		Subject:               pdfSubject,
+		Keywords:              pdfKeywords,
+		CreationDate:          created,
+		Cover: pdf.Cover{
+			Enabled: coverEnabled || coverTitle != "" || coverSubtitle != "" || coverAuthor != "" ||
+				coverDate != "" || coverLogo != "",
+			Title:    coverTitle,
+			Subtitle: coverSubtitle,
+			Author:   coverAuthor,
+			Date:     coverDate,
+			Logo:     coverLogo,
+		},
+		ContentsPage:  pdfContents,
+		ContentsDepth: pdfContentsDepth,
+		Fonts:         pdf.Fonts{Body: body, Mono: mono},
+		Watermark: pdf.Watermark{
+			Text:     watermarkText,
+			Image:    watermarkImage,
+			Opacity:  watermarkOpacity,
+			Angle:    watermarkAngle,
+			Color:    watermarkColor,
+			FontSize: watermarkSize,
+			Over:     watermarkOver,
+		},
+	}, nil
+}
 
-	conv, err := buildConverter(options, format, pageSize, margin)
+// parseFonts parses the values of a repeated font flag into a fallback chain.
+func parseFonts(values []string) ([]pdf.Font, error) {
+	fonts := make([]pdf.Font, 0, len(values))
+	for _, v := range values {
+		f, err := pdf.ParseFont(v)
+		if err != nil {
+			return nil, err
+		}
+		fonts = append(fonts, f)
+	}
+	return fonts, nil
+}
+
+// runConversion converts one file with options into format.
+func runConversion(inputFilePath, outputFilePath string, options converter.Options, format string) error {
+	conv, err := buildConverter(options, format)
 	if err != nil {
 		return err
 	}
@@ -42,21 +222,40 @@ func runConversion(
 }
 
 // buildConverter returns a converter.Converter implementation for the given
-// output format. PDF wraps the HTML pipeline; HTML uses it directly.
-func buildConverter(options converter.Options, format, pageSize, margin string) (converter.Converter, error) {
+// output format. PDF wraps the HTML pipeline, laid out by the page layout
+// flags; HTML uses it directly.
+func buildConverter(options converter.Options, format string) (converter.Converter, error) {
+	if err := validateConverterOptions(options); err != nil {
+		return nil, err
+	}
 	if format == formatPDF {
-		m, err := pdf.ParseMargin(margin)
-		if err != nil {
-			return nil, fmt.Errorf("invalid PDF options: %w", err)
-		}
-		pdfConv, err := pdf.New(options, pdf.Options{
-			PageSize: pageSize,
-			Margins:  pdf.Margins{Top: m, Right: m, Bottom: m, Left: m},
-		})
-		if err != nil {
-			return nil, fmt.Errorf("invalid PDF options: %w", err)
-		}
-		return pdfConv, nil
+		return newPDFConverter(options)
 	}
 	return converter.NewCompleteConverter(options), nil
 }
+
+// validateConverterOptions checks the highlighting and table of contents
+// options.
+func validateConverterOptions(options converter.Options) error {
+	if err := converter.ValidateHighlightStyle(options.HighlightStyle); err != nil {
+		return fmt.Errorf("invalid highlighting options: %w", err)
+	}
+	if err := converter.ValidateTOC(options.TOC); err != nil {
+		return fmt.Errorf("invalid table of contents options: %w", err)
+	}
+	return nil
+}
+
+// newPDFConverter returns a PDF converter wrapping the HTML pipeline
+// configured by options, laid out by the page layout flags.
+func newPDFConverter(options converter.Options) (*pdf.Converter, error) {
+	pdfOpts, err := pdfOptions()
+	if err != nil {
+		return nil, fmt.Errorf("invalid PDF options: %w", err)
+	}
+	pdfConv, err := pdf.New(options, pdfOpts)
+	if err != nil {
+		return nil, fmt.Errorf("invalid PDF options: %w", err)
+	}
+	return pdfConv, nil
+}
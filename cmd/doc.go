@@ -1,7 +1,7 @@
 // Package cmd implements the CLI commands for mdtohtml.
 //
 // It provides the root command along with subcommands for converting,
-// batch processing, and validating Markdown files. All commands share
-// common flags for typographic options (smartypants, LaTeX dashes,
-// fractions) and safe mode.
+// batch processing, combining into PDF books, previewing, and validating
+// Markdown files. All commands share common flags for typographic options
+// (smartypants, LaTeX dashes, fractions) and safe mode.
 package cmd
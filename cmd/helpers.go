@@ -31,6 +31,17 @@ var (
 	errBothCSSSourcesProvided = errors.New("--css-file and --css-url are mutually exclusive")
 	// errUnknownFormat is returned when --format is set to an unrecognised value.
 	errUnknownFormat = errors.New("unknown --format value (expected html or pdf)")
+
+	// errInvalidJobs is returned when --jobs is negative.
+	errInvalidJobs = errors.New("--jobs must be zero or positive")
+	// errInvalidFiles is returned when validating several files and some of
+	// them have error-severity findings or cannot be validated.
+	errInvalidFiles = errors.New("files failed validation")
+
+	// errNoChapters is returned when book is given neither chapter files nor --summary.
+	errNoChapters = errors.New("no chapters: pass chapter files or --summary")
+	// errSummaryWithChapters is returned when book is given both chapter files and --summary.
+	errSummaryWithChapters = errors.New("--summary cannot be combined with chapter files")
 )
 
 // resolveFormat returns the output format to use. If explicit is non-empty it
@@ -19,7 +19,42 @@ var (
 	noCSS             bool
 	outputFormat      string // "", "html", "pdf"; empty = auto-detect from extension
 	pageSize          string // PDF page size, e.g. "A4", "Letter"
-	marginFlag        string // PDF margin, e.g. "1.25in", "90", "2.5cm"
+	marginFlag        string // PDF margins, e.g. "1.25in", "1in 0.75in", "2.5cm"
+	orientation       string // PDF page orientation: "portrait" or "landscape"
+	headerTemplate    string // PDF running header, e.g. "{title}||{page}/{pages}"
+	footerTemplate    string // PDF running footer
+	headerSkipFirst   bool   // no PDF header and footer on the first page
+	pdfAuthor         string // PDF Author, overriding the front matter author
+	pdfSubject        string // PDF Subject, overriding the front matter description
+	pdfKeywords       string // PDF Keywords, overriding the front matter keywords
+	coverEnabled      bool   // PDF cover page; also enabled by any cover text or logo
+	coverTitle        string // PDF cover title, overriding the document title
+	coverSubtitle     string
+	coverAuthor       string   // PDF cover author, overriding the PDF author
+	coverDate         string   // PDF cover date, overriding the creation date
+	coverLogo         string   // PNG or JPEG image on the PDF cover
+	pdfContents       bool     // PDF contents page with page numbers
+	pdfContentsDepth  int      // deepest heading level on the PDF contents page
+	pageBreakH1       bool     // start every H1 on a new page
+	pdfFonts          []string // PDF body font chain, each REGULAR[,BOLD[,ITALIC[,BOLDITALIC]]]
+	pdfMonoFonts      []string // PDF code font chain
+	watermarkText     string   // text drawn on every PDF page
+	watermarkImage    string   // PNG or JPEG image drawn on every PDF page
+	watermarkOpacity  float64  // PDF watermark opacity from 0 to 1
+	watermarkAngle    float64  // PDF watermark text angle in degrees
+	watermarkColor    string   // PDF watermark text color
+	watermarkSize     float64  // PDF watermark font size in points
+	watermarkOver     bool     // draw the PDF watermark over the content
+	highlightStyle    string   // Chroma style for fenced code; empty disables highlighting
+	highlightClasses  bool
+	lineNumbers       bool
+	tocEnabled        bool
+	tocMinDepth       int
+	tocMaxDepth       int
+	watchMode         bool
+	selfContained     bool
+	inlineStylesheets bool
+	rewriteLinks      bool // convert only; batch uses batchRewriteLinks, which defaults to true
 )
 
 const defaultMarginFlag = "1.25in"
@@ -63,16 +98,18 @@ func init() {
 	rootCmd.Flags().BoolVar(&noCSS, "no-css", false, "Disable CSS injection entirely")
 	rootCmd.Flags().StringVar(&outputFormat, "format", "",
 		`Output format: "html" or "pdf" (default: auto-detect from output file extension)`)
-	rootCmd.Flags().StringVar(&pageSize, "page-size", "A4",
-		`PDF page size when --format=pdf: A4, Letter, Legal, A3, A5, Tabloid`)
-	rootCmd.Flags().StringVar(&marginFlag, "margin", defaultMarginFlag,
-		`PDF page margin (units: pt, in, cm, mm; bare numbers = pt)`)
+	addPDFFlags(rootCmd)
+	addHighlightFlags(rootCmd)
+	addTOCFlags(rootCmd)
+	addSelfContainedFlags(rootCmd)
+	addRewriteLinksFlag(rootCmd, &rewriteLinks, false)
+	addWatchFlag(rootCmd)
 	rootCmd.Version = Version
 	rootCmd.SetVersionTemplate(`{{.Version}}
 `)
 }
 
-func convert(_ *cobra.Command, args []string) error {
+func convert(cmd *cobra.Command, args []string) error {
 	inputFilePath := args[0]
 	outputFilePath := args[1]
 
@@ -80,6 +117,16 @@ func convert(_ *cobra.Command, args []string) error {
 		return err
 	}
 
+	if watchMode {
+		return watchConvert(cmd.Context(), inputFilePath, outputFilePath, func() error {
+			return convertFile(inputFilePath, outputFilePath)
+		})
+	}
+	return convertFile(inputFilePath, outputFilePath)
+}
+
+// convertFile resolves the CSS and format flags and converts one file.
+func convertFile(inputFilePath, outputFilePath string) error {
 	source, additional, err := resolveCSSOptions(
 		cssFile, cssURL, additionalCSSFile, noCSS,
 	)
@@ -87,16 +134,13 @@ func convert(_ *cobra.Command, args []string) error {
 		return err
 	}
 
-	css := cssOptions{
-		source: source, additional: additional, noCSS: noCSS,
-	}
 	format, err := resolveFormat(outputFormat, outputFilePath)
 	if err != nil {
 		return err
 	}
-	return runConversion(
-		inputFilePath, outputFilePath,
-		smartypants, latexdashes, fractions, safeMode, css,
-		format, pageSize, marginFlag,
-	)
+	options := converterOptions(source, additional)
+	if rewriteLinks {
+		options.MarkdownLinkExt = extForFormat(format)
+	}
+	return runConversion(inputFilePath, outputFilePath, options, format)
 }
\ No newline at end of file
@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sgaunet/mdtohtml/pkg/converter"
+	"github.com/sgaunet/mdtohtml/pkg/server"
+	"github.com/sgaunet/mdtohtml/pkg/watcher"
+)
+
+var (
+	serveAddr string
+	noReload  bool
+)
+
+const (
+	defaultServeAddr = "localhost:8080"
+	// shutdownTimeout bounds how long in-flight requests may take to finish
+	// once the server is stopped.
+	shutdownTimeout = 5 * time.Second
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve [dir]",
+	Short: "Preview Markdown files in the browser with live reload",
+	Long: `Serve the Markdown files of a directory as HTML on a local address.
+Pages are rendered on request with the same options as convert, images and other
+files are served from the directory as is, and open pages reload themselves when
+a file in the directory or a CSS file changes.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: serve,
+	Example: `  mdtohtml serve
+  mdtohtml serve ./docs --addr localhost:3000
+  mdtohtml serve ./docs --highlight-style github --toc`,
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().StringVarP(&serveAddr, "addr", "a", defaultServeAddr, "Address to listen on (host:port)")
+	serveCmd.Flags().BoolVar(&noReload, "no-reload", false, "Do not reload open pages when files change")
+	serveCmd.Flags().BoolVar(&smartypants, "smartypants", true,
+		`Convert quotes to curly quotes, -- to en/em-dash, ... to ellipsis`)
+	serveCmd.Flags().BoolVar(&latexdashes, "latexdashes", true,
+		`LaTeX-style dashes: --- for em-dash, -- for en-dash (requires --smartypants)`)
+	serveCmd.Flags().BoolVar(&fractions, "fractions", true,
+		`Convert fractions: 1/2 to ½, 1/4 to ¼, 3/4 to ¾`)
+	serveCmd.Flags().BoolVar(&safeMode, "safe-mode", false, "Disable raw HTML pass-through to prevent XSS")
+	serveCmd.Flags().StringVar(&cssFile, "css-file", "", "Path to a CSS file to use instead of the default GitHub CSS")
+	serveCmd.Flags().StringVar(&cssURL, "css-url", "", "URL to fetch CSS from instead of the default GitHub CSS")
+	serveCmd.Flags().StringVar(&additionalCSSFile, "additional-css", "", "Path to a CSS file to append to the default CSS")
+	serveCmd.Flags().BoolVar(&noCSS, "no-css", false, "Disable CSS injection entirely")
+	addHighlightFlags(serveCmd)
+	addTOCFlags(serveCmd)
+}
+
+func serve(cmd *cobra.Command, args []string) error {
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
+	}
+	if err := validateInputDir(dir); err != nil {
+		return err
+	}
+
+	conv, err := newServeConverter()
+	if err != nil {
+		return err
+	}
+	srv, err := server.New(dir, conv)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = srv.Close() }()
+
+	listener, err := net.Listen("tcp", serveAddr)
+	if err != nil {
+		return fmt.Errorf("serve: %w", err)
+	}
+	httpSrv := &http.Server{Handler: srv, ReadHeaderTimeout: shutdownTimeout}
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- httpSrv.Serve(listener) }()
+	fmt.Printf("Serving %s at http://%s/\n", dir, listener.Addr())
+
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+	watchErr := make(chan error, 1)
+	go func() { watchErr <- watchServe(ctx, dir, srv) }()
+
+	select {
+	case err = <-serveErr:
+		cancel()
+		<-watchErr
+	case err = <-watchErr:
+	}
+
+	// Close ends the live-reload streams, which would otherwise keep Shutdown waiting.
+	_ = srv.Close()
+	shutdownCtx, stop := context.WithTimeout(context.WithoutCancel(cmd.Context()), shutdownTimeout)
+	defer stop()
+	if shutdownErr := httpSrv.Shutdown(shutdownCtx); err == nil && shutdownErr != nil {
+		err = fmt.Errorf("serve: %w", shutdownErr)
+	}
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// watchServe reloads the open pages whenever a file below dir or a CSS file
+// changes, rebuilding the converter first when a CSS file changed. It blocks
+// until ctx is cancelled or the process is interrupted. With --no-reload it
+// only waits.
+func watchServe(ctx context.Context, dir string, srv *server.Server) error {
+	if noReload {
+		ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		<-ctx.Done()
+		return nil
+	}
+	w, err := watcher.New(watcher.DefaultDebounce)
+	if err != nil {
+		return err
+	}
+	if err := w.AddDir(dir, true); err != nil {
+		_ = w.Close()
+		return err
+	}
+	cssPaths := cssWatchPaths()
+	for _, p := range cssPaths {
+		if err := w.AddFile(p); err != nil {
+			_ = w.Close()
+			return err
+		}
+	}
+
+	return watch(ctx, w, func(changed []string) {
+		if slices.ContainsFunc(changed, func(p string) bool { return slices.Contains(cssPaths, p) }) {
+			conv, err := newServeConverter()
+			if err != nil {
+				reportWatchError(err)
+				return
+			}
+			srv.SetConverter(conv)
+		}
+		srv.Reload()
+	})
+}
+
+// newServeConverter resolves the CSS flags and returns the HTML converter
+// used to render pages.
+func newServeConverter() (converter.Converter, error) {
+	source, additional, err := resolveCSSOptions(
+		cssFile, cssURL, additionalCSSFile, noCSS,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return buildConverter(converterOptions(source, additional), formatHTML)
+}
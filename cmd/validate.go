@@ -1,39 +1,175 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
-	"github.com/spf13/cobra"
 	"github.com/sgaunet/mdtohtml/pkg/converter"
+	"github.com/sgaunet/mdtohtml/pkg/processor"
 	"github.com/sgaunet/mdtohtml/pkg/validator"
+	"github.com/spf13/cobra"
 )
 
+var (
+	enabledRules         []string
+	disabledRules        []string
+	ruleSeverity         map[string]string
+	validateOutputFormat string
+
+	checkExternal        bool
+	externalConcurrency  int
+	externalTimeout      time.Duration
+	externalRetries      int
+	externalHostInterval time.Duration
+	externalAllow        []string
+	externalDeny         []string
+	externalCache        string
+	externalCacheTTL     time.Duration
+)
+
+// severityOff disables a rule in --severity.
+const severityOff = "off"
+
 var validateCmd = &cobra.Command{
-	Use:   "validate [input.md]",
+	Use:   "validate [path...]",
 	Short: "Validate Markdown syntax without converting",
 	Long: `Validate Markdown syntax without generating output.
-This checks if the file can be parsed successfully by the Goldmark processor.`,
-	Args: cobra.ExactArgs(1),
+This checks if each file can be parsed successfully by the Goldmark processor
+and runs lint rules over the parsed document. Findings of error severity make
+validation fail; warnings and info findings are reported only.
+
+Paths may be files or directories. Directories are searched for files
+matching --pattern, including subdirectories with --recursive, like the
+batch command does. Files are validated in parallel.
+
+Findings are printed as text by default. --output-format selects json,
+sarif (for code scanning tools) or github (workflow commands that annotate
+pull requests).
+
+--check-external also requests every absolute http(s) URL and reports those
+that fail or answer 4xx/5xx (external-link) and those that redirect
+(external-redirect). Each URL is requested once per run; results can be
+kept between runs with --external-cache.
+
+Lint rules (default severity):
+` + ruleList(),
+	Args: cobra.MinimumNArgs(1),
 	RunE: validateMarkdown,
 	Example: `  mdtohtml validate README.md
-  mdtohtml validate document.md`,
+  mdtohtml validate README.md docs --recursive
+  mdtohtml validate document.md --disable single-h1,image-alt
+  mdtohtml validate document.md --severity heading-increment=error
+  mdtohtml validate document.md --output-format sarif > results.sarif
+  mdtohtml validate docs -r --check-external --external-deny localhost --external-cache .cache/links.json`,
 }
 
 func init() {
 	rootCmd.AddCommand(validateCmd)
-	
+
+	validateCmd.Flags().StringVarP(&pattern, "pattern", "p", "*.md", "File pattern to match in directories")
+	validateCmd.Flags().BoolVarP(&recursive, "recursive", "r", false, "Search directories recursively")
+	validateCmd.Flags().IntVarP(&jobs, "jobs", "j", 0, "Number of files to validate in parallel (0 = GOMAXPROCS)")
 	validateCmd.Flags().BoolVar(&smartypants, "smartypants", true,
 		`Convert quotes to curly quotes, -- to en/em-dash, ... to ellipsis`)
 	validateCmd.Flags().BoolVar(&latexdashes, "latexdashes", true,
 		`LaTeX-style dashes: --- for em-dash, -- for en-dash (requires --smartypants)`)
 	validateCmd.Flags().BoolVar(&fractions, "fractions", true,
 		`Convert fractions: 1/2 to ½, 1/4 to ¼, 3/4 to ¾`)
+	validateCmd.Flags().StringSliceVar(&enabledRules, "enable", nil,
+		"Run only the listed lint rules (comma-separated)")
+	validateCmd.Flags().StringSliceVar(&disabledRules, "disable", nil,
+		"Do not run the listed lint rules (comma-separated)")
+	validateCmd.Flags().StringToStringVar(&ruleSeverity, "severity", nil,
+		`Override rule severities, e.g. heading-increment=error (levels: info, warning, error, off)`)
+	validateCmd.Flags().StringVar(&validateOutputFormat, "output-format", string(validator.FormatText),
+		`Diagnostics output format: text, json, sarif or github`)
+	validateCmd.Flags().BoolVar(&checkExternal, "check-external", false,
+		"Request absolute http(s) URLs and report failing and redirecting links")
+	validateCmd.Flags().IntVar(&externalConcurrency, "external-concurrency", validator.DefaultExternalConcurrency,
+		"Maximum number of external requests in flight")
+	validateCmd.Flags().DurationVar(&externalTimeout, "external-timeout", validator.DefaultExternalTimeout,
+		"Timeout of each external request")
+	validateCmd.Flags().IntVar(&externalRetries, "external-retries", 1,
+		"Times to retry an external request after a network error, 429 or 5xx")
+	validateCmd.Flags().DurationVar(&externalHostInterval, "external-host-interval", 0,
+		"Minimum time between requests to the same host (e.g. 500ms)")
+	validateCmd.Flags().StringSliceVar(&externalAllow, "external-allow", nil,
+		`Only check URLs matching these host globs or URL prefixes (e.g. "*.example.com")`)
+	validateCmd.Flags().StringSliceVar(&externalDeny, "external-deny", nil,
+		`Never check URLs matching these host globs or URL prefixes (e.g. "localhost,https://example.com/private/")`)
+	validateCmd.Flags().StringVar(&externalCache, "external-cache", "",
+		"File to cache external link results in between runs")
+	validateCmd.Flags().DurationVar(&externalCacheTTL, "external-cache-ttl", validator.DefaultExternalCacheTTL,
+		"How long cached external link results are reused")
+}
+
+// ruleList describes the lint rules for the validate help text.
+func ruleList() string {
+	var b strings.Builder
+	for _, r := range validator.Rules() {
+		fmt.Fprintf(&b, "  %-22s %s (%s)\n", r.ID, r.Description, r.Severity)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// lintConfig builds the lint configuration from the validate flags, with an
+// external link checker when --check-external is set.
+func lintConfig() (validator.LintConfig, error) {
+	config := validator.LintConfig{
+		Enabled:  enabledRules,
+		Disabled: disabledRules,
+		Severity: make(map[string]validator.Severity),
+	}
+	for id, level := range ruleSeverity {
+		if strings.EqualFold(level, severityOff) {
+			config.Disabled = append(config.Disabled, id)
+			continue
+		}
+		s, err := validator.ParseSeverity(level)
+		if err != nil {
+			return config, fmt.Errorf("invalid --severity for %s: %w", id, err)
+		}
+		config.Severity[id] = s
+	}
+	if !checkExternal {
+		return config, nil
+	}
+	checker, err := validator.NewExternalChecker(validator.ExternalOptions{
+		Concurrency:  externalConcurrency,
+		Timeout:      externalTimeout,
+		Retries:      externalRetries,
+		HostInterval: externalHostInterval,
+		Allow:        externalAllow,
+		Deny:         externalDeny,
+		CacheFile:    externalCache,
+		CacheTTL:     externalCacheTTL,
+	})
+	if err != nil {
+		return config, fmt.Errorf("invalid external link options: %w", err)
+	}
+	config.External = checker
+	return config, nil
 }
 
 func validateMarkdown(_ *cobra.Command, args []string) error {
-	inputFilePath := args[0]
+	if jobs < 0 {
+		return fmt.Errorf("%w: %d", errInvalidJobs, jobs)
+	}
+	format, err := validator.ParseOutputFormat(validateOutputFormat)
+	if err != nil {
+		return err
+	}
+	config, err := lintConfig()
+	if err != nil {
+		return err
+	}
 
-	if err := validateInputFile(inputFilePath); err != nil {
+	files, err := validationFiles(args, format)
+	if err != nil {
 		return err
 	}
 
@@ -45,13 +181,132 @@ func validateMarkdown(_ *cobra.Command, args []string) error {
 	}
 
 	conv := converter.NewGoldmarkConverter(options)
-	val := validator.NewGoldmarkValidator(conv)
+	val, err := validator.NewGoldmarkValidatorWithLint(conv, config)
+	if err != nil {
+		return err
+	}
 
-	// Validate the file
-	if err := val.ValidateFile(inputFilePath); err != nil {
-		return fmt.Errorf("validation failed for %s: %w", inputFilePath, err)
+	results := validator.ValidateFiles(val, files, jobs)
+	if config.External != nil {
+		// A cache that cannot be written only costs requests next run.
+		if err := config.External.SaveCache(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+	// A single file argument keeps the single-file report; directories and
+	// several arguments get the total, even when they hold one file.
+	if len(args) == 1 && len(files) == 1 && files[0] == args[0] {
+		return reportFile(results[0], format)
+	}
+	return reportFiles(results, format)
+}
+
+// validationFiles expands the validate arguments into the files to validate.
+// Files are taken as given; directories contribute the files that batch would
+// convert with the same --pattern and --recursive flags. Each file is
+// listed once, in argument order.
+func validationFiles(args []string, format validator.OutputFormat) ([]string, error) {
+	var files []string
+	seen := make(map[string]bool)
+	add := func(file string) {
+		if clean := filepath.Clean(file); !seen[clean] {
+			seen[clean] = true
+			files = append(files, file)
+		}
+	}
+
+	opts := processor.ProcessOptions{Pattern: pattern, Recursive: recursive}
+	for _, arg := range args {
+		if err := validateInputFile(arg); !errors.Is(err, errNotAFile) {
+			if err != nil {
+				return nil, err
+			}
+			add(arg)
+			continue
+		}
+		found, err := processor.FindFiles(arg, opts)
+		if err != nil {
+			return nil, fmt.Errorf("error finding files matching '%s' in '%s': %w", pattern, arg, err)
+		}
+		if len(found) == 0 && format == validator.FormatText {
+			fmt.Printf("No files matching pattern '%s' found in '%s'\n", pattern, arg)
+		}
+		for _, f := range found {
+			add(f)
+		}
+	}
+	return files, nil
+}
+
+// reportFile prints the outcome of validating a single file.
+func reportFile(res validator.FileResult, format validator.OutputFormat) error {
+	if res.Err != nil {
+		return fmt.Errorf("validation failed for %s: %w", res.Path, res.Err)
+	}
+	if err := validator.WriteDiagnostics(os.Stdout, format, res.Diagnostics); err != nil {
+		return err
+	}
+	if n := validator.CountSeverity(res.Diagnostics, validator.SeverityError); n > 0 {
+		return fmt.Errorf("validation failed for %s: %w: %d error(s)", res.Path, validator.ErrLint, n)
+	}
+	// Machine-readable formats keep stdout free of anything else.
+	if format == validator.FormatText {
+		fmt.Println(fileSummary(res))
+	}
+	return nil
+}
+
+// reportFiles prints the outcome of validating several files: in text format
+// the findings and a summary line per file followed by a total, otherwise a
+// single report covering every file. Files that could not be validated are
+// reported on stderr in machine-readable formats.
+func reportFiles(results []validator.FileResult, format validator.OutputFormat) error {
+	var all []validator.Diagnostic
+	invalid := 0
+	for _, res := range results {
+		if res.Err != nil || validator.CountSeverity(res.Diagnostics, validator.SeverityError) > 0 {
+			invalid++
+		}
+		if format != validator.FormatText {
+			if res.Err != nil {
+				fmt.Fprintf(os.Stderr, "✗ %s: %v\n", res.Path, res.Err)
+			}
+			all = append(all, res.Diagnostics...)
+			continue
+		}
+		if err := validator.WriteDiagnostics(os.Stdout, format, res.Diagnostics); err != nil {
+			return err
+		}
+		fmt.Println(fileSummary(res))
+	}
+
+	if format == validator.FormatText {
+		if len(results) > 0 {
+			fmt.Printf("\nValidated %d files: %d valid, %d invalid\n", len(results), len(results)-invalid, invalid)
+		}
+	} else if err := validator.WriteDiagnostics(os.Stdout, format, all); err != nil {
+		return err
 	}
 
-	fmt.Printf("✓ %s is valid Markdown\n", inputFilePath)
+	if invalid > 0 {
+		return fmt.Errorf("%w: %d of %d", errInvalidFiles, invalid, len(results))
+	}
 	return nil
-}
\ No newline at end of file
+}
+
+// fileSummary returns the summary line printed for a validated file.
+func fileSummary(res validator.FileResult) string {
+	if res.Err != nil {
+		return fmt.Sprintf("✗ %s: %v", res.Path, res.Err)
+	}
+	errs := validator.CountSeverity(res.Diagnostics, validator.SeverityError)
+	warnings := validator.CountSeverity(res.Diagnostics, validator.SeverityWarning)
+	switch {
+	case errs > 0:
+		return fmt.Sprintf("✗ %s: %d error(s), %d warning(s)", res.Path, errs, warnings)
+	case warnings > 0:
+		return fmt.Sprintf("✓ %s is valid Markdown (%d warning(s))", res.Path, warnings)
+	default:
+		return fmt.Sprintf("✓ %s is valid Markdown", res.Path)
+	}
+}
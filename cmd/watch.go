@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sgaunet/mdtohtml/pkg/processor"
+	"github.com/sgaunet/mdtohtml/pkg/watcher"
+)
+
+// addWatchFlag registers the --watch flag on cmd.
+func addWatchFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVarP(&watchMode, "watch", "w", false,
+		"Keep running and rebuild outputs when the input or CSS files change")
+}
+
+// cssWatchPaths returns the local CSS files whose changes require a rebuild.
+func cssWatchPaths() []string {
+	var paths []string
+	for _, p := range []string{cssFile, additionalCSSFile} {
+		if p != "" {
+			paths = append(paths, filepath.Clean(p))
+		}
+	}
+	return paths
+}
+
+// reportWatchError prints an error without stopping watch mode.
+func reportWatchError(err error) {
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+	}
+}
+
+// watch runs onChange for every debounced batch of changes until the
+// process is interrupted. w must already watch the relevant paths.
+func watch(ctx context.Context, w *watcher.Watcher, onChange func(changed []string)) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	defer func() { _ = w.Close() }()
+
+	fmt.Println("Watching for changes (press Ctrl+C to stop)...")
+	if err := w.Run(ctx, onChange); err != nil {
+		return fmt.Errorf("watch mode: %w", err)
+	}
+	return nil
+}
+
+// watchConvert converts inputPath once and then again whenever it or one of
+// the CSS files changes. Conversion errors are reported and watching goes on.
+func watchConvert(ctx context.Context, inputPath, outputPath string, run func() error) error {
+	rebuild := func() {
+		if err := run(); err != nil {
+			reportWatchError(err)
+			return
+		}
+		fmt.Printf("Converted %s -> %s\n", inputPath, outputPath)
+	}
+	rebuild()
+
+	w, err := watcher.New(watcher.DefaultDebounce)
+	if err != nil {
+		return err
+	}
+	for _, p := range append([]string{inputPath}, cssWatchPaths()...) {
+		if err := w.AddFile(p); err != nil {
+			_ = w.Close()
+			return err
+		}
+	}
+	return watch(ctx, w, func(_ []string) {
+		rebuild()
+	})
+}
+
+// watchBatch converts inputDir once and then reconverts the matching
+// Markdown files that change. A change to a CSS file rebuilds the converter
+// and reconverts everything. Errors are reported and watching goes on.
+func watchBatch(
+	ctx context.Context, inputDir string, options processor.ProcessOptions,
+	newProcessor func() (*processor.FileProcessor, error),
+) error {
+	proc, err := newProcessor()
+	if err != nil {
+		return err
+	}
+	reportWatchError(proc.ProcessDirectory(inputDir, options))
+
+	w, err := watcher.New(watcher.DefaultDebounce)
+	if err != nil {
+		return err
+	}
+	if err := w.AddDir(inputDir, options.Recursive); err != nil {
+		_ = w.Close()
+		return err
+	}
+	cssPaths := cssWatchPaths()
+	for _, p := range cssPaths {
+		if err := w.AddFile(p); err != nil {
+			_ = w.Close()
+			return err
+		}
+	}
+
+	return watch(ctx, w, func(changed []string) {
+		if slices.ContainsFunc(changed, func(p string) bool { return slices.Contains(cssPaths, p) }) {
+			next, err := newProcessor()
+			if err != nil {
+				reportWatchError(err)
+				return
+			}
+			proc = next
+			reportWatchError(proc.ProcessDirectory(inputDir, options))
+			return
+		}
+
+		var files []string
+		for _, p := range changed {
+			if info, err := os.Stat(p); err == nil && info.Mode().IsRegular() && options.Matches(inputDir, p) {
+				files = append(files, p)
+			}
+		}
+		reportWatchError(proc.ProcessFiles(files, inputDir, options))
+	})
+}
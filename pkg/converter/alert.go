@@ -0,0 +1,191 @@
+package converter
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// alertType describes one GitHub alert variant: the title shown above the
+// content, its accent colour, and the octicon drawn next to the title.
+type alertType struct {
+	title string
+	color string
+	icon  string
+	path  string
+}
+
+// alertTypes maps the lowercased marker keyword (as in "> [!NOTE]") to its
+// presentation. Icons are the 16px octicons GitHub uses for each alert.
+var alertTypes = map[string]alertType{
+	"note": {
+		title: "Note", color: "#0969da", icon: "info",
+		path: "M0 8a8 8 0 1 1 16 0A8 8 0 0 1 0 8Zm8-6.5a6.5 6.5 0 1 0 0 13 6.5 6.5 0 0 0 0-13ZM6.5 7.75A.75.75 0 0 1 " +
+			"7.25 7h1a.75.75 0 0 1 .75.75v2.75h.25a.75.75 0 0 1 0 1.5h-2a.75.75 0 0 1 0-1.5h.25v-2h-.25a.75.75 0 0 " +
+			"1-.75-.75ZM8 6a1 1 0 1 1 0-2 1 1 0 0 1 0 2Z",
+	},
+	"tip": {
+		title: "Tip", color: "#1a7f37", icon: "light-bulb",
+		path: "M8 1.5c-2.363 0-4 1.69-4 3.75 0 .984.424 1.625.984 2.304l.214.253c.223.264.47.556.673.848.284.411.537.896" +
+			".621 1.49a.75.75 0 0 1-1.484.211c-.04-.282-.163-.547-.37-.847a8.456 8.456 0 0 0-.542-.68c-.084-.1-.173-.205" +
+			"-.268-.32C3.201 7.75 2.5 6.766 2.5 5.25 2.5 2.31 4.863 0 8 0s5.5 2.31 5.5 5.25c0 1.516-.701 2.5-1.328 3.259" +
+			"-.095.115-.184.22-.268.319-.207.245-.383.453-.541.681-.208.3-.33.565-.37.847a.751.751 0 0 1-1.485-.212c.084" +
+			"-.593.337-1.078.621-1.489.203-.292.45-.584.673-.848.075-.088.147-.173.213-.253.561-.679.985-1.32.985-2.304 " +
+			"0-2.06-1.637-3.75-4-3.75ZM5.75 12h4.5a.75.75 0 0 1 0 1.5h-4.5a.75.75 0 0 1 0-1.5ZM6 15.25a.75.75 0 0 1 .75" +
+			"-.75h2.5a.75.75 0 0 1 0 1.5h-2.5a.75.75 0 0 1-.75-.75Z",
+	},
+	"important": {
+		title: "Important", color: "#8250df", icon: "report",
+		path: "M0 1.75C0 .784.784 0 1.75 0h12.5C15.216 0 16 .784 16 1.75v9.5A1.75 1.75 0 0 1 14.25 13H8.06l-2.573 " +
+			"2.573A1.458 1.458 0 0 1 3 14.543V13H1.75A1.75 1.75 0 0 1 0 11.25Zm1.75-.25a.25.25 0 0 0-.25.25v9.5c0 .138" +
+			".112.25.25.25h2a.75.75 0 0 1 .75.75v2.19l2.72-2.72a.749.749 0 0 1 .53-.22h6.5a.25.25 0 0 0 .25-.25v-9.5a" +
+			".25.25 0 0 0-.25-.25Zm7 2.25v2.5a.75.75 0 0 1-1.5 0v-2.5a.75.75 0 0 1 1.5 0ZM9 9a1 1 0 1 1-2 0 1 1 0 0 1 2 0Z",
+	},
+	"warning": {
+		title: "Warning", color: "#9a6700", icon: "alert",
+		path: "M6.457 1.047c.659-1.234 2.427-1.234 3.086 0l6.082 11.378A1.75 1.75 0 0 1 14.082 15H1.918a1.75 1.75 0 0 " +
+			"1-1.543-2.575Zm1.763.707a.25.25 0 0 0-.44 0L1.698 13.132a.25.25 0 0 0 .22.368h12.164a.25.25 0 0 0 .22" +
+			"-.368Zm.53 3.996v2.5a.75.75 0 0 1-1.5 0v-2.5a.75.75 0 0 1 1.5 0ZM9 11a1 1 0 1 1-2 0 1 1 0 0 1 2 0Z",
+	},
+	"caution": {
+		title: "Caution", color: "#d1242f", icon: "stop",
+		path: "M4.47.22A.749.749 0 0 1 5 0h6c.199 0 .389.079.53.22l4.25 4.25c.141.14.22.331.22.53v6a.749.749 0 0 1-.22" +
+			".53l-4.25 4.25A.749.749 0 0 1 11 16H5a.749.749 0 0 1-.53-.22L.22 11.53A.749.749 0 0 1 0 11V5c0-.199.079" +
+			"-.389.22-.53Zm.84 1.28L1.5 5.31v5.38l3.81 3.81h5.38l3.81-3.81V5.31L10.69 1.5ZM8 4a.75.75 0 0 1 .75.75v3.5a" +
+			".75.75 0 0 1-1.5 0v-3.5A.75.75 0 0 1 8 4Zm0 8a1 1 0 1 1 0-2 1 1 0 0 1 0 2Z",
+	},
+}
+
+// KindAlert is the ast.NodeKind of a GitHub-style alert block.
+var KindAlert = ast.NewNodeKind("Alert")
+
+// alertNode replaces a blockquote that starts with an alert marker. Its
+// children are the blockquote's content without the marker.
+type alertNode struct {
+	ast.BaseBlock
+	AlertType string
+}
+
+// Kind implements ast.Node.
+func (n *alertNode) Kind() ast.NodeKind {
+	return KindAlert
+}
+
+// Dump implements ast.Node.
+func (n *alertNode) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"AlertType": n.AlertType}, nil)
+}
+
+// alertExtension renders GitHub alerts ("> [!NOTE]", "> [!TIP]",
+// "> [!IMPORTANT]", "> [!WARNING]" and "> [!CAUTION]") with the markup and
+// classes github.com uses.
+type alertExtension struct{}
+
+// Extend implements goldmark.Extender.
+func (e *alertExtension) Extend(m goldmark.Markdown) {
+	const transformerPriority, rendererPriority = 900, 500
+	m.Parser().AddOptions(parser.WithASTTransformers(
+		util.Prioritized(&alertTransformer{}, transformerPriority),
+	))
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(&alertRenderer{}, rendererPriority),
+	))
+}
+
+// alertTransformer turns top-level blockquotes whose first line is an alert
+// marker into alertNodes. Like GitHub, alerts nested inside other blocks and
+// markers without any content are left as ordinary blockquotes.
+type alertTransformer struct{}
+
+// Transform implements parser.ASTTransformer.
+func (t *alertTransformer) Transform(doc *ast.Document, reader text.Reader, _ parser.Context) {
+	source := reader.Source()
+	for n := doc.FirstChild(); n != nil; {
+		next := n.NextSibling()
+		if quote, ok := n.(*ast.Blockquote); ok {
+			if alert := newAlert(quote, source); alert != nil {
+				doc.ReplaceChild(doc, quote, alert)
+			}
+		}
+		n = next
+	}
+}
+
+// newAlert returns the alertNode for quote, or nil when quote does not start
+// with an alert marker line followed by content.
+func newAlert(quote *ast.Blockquote, source []byte) *alertNode {
+	para, ok := quote.FirstChild().(*ast.Paragraph)
+	if !ok || para.Lines().Len() == 0 {
+		return nil
+	}
+	first := para.Lines().At(0)
+	marker := strings.ToLower(string(bytes.TrimSpace(first.Value(source))))
+	if !strings.HasPrefix(marker, "[!") || !strings.HasSuffix(marker, "]") {
+		return nil
+	}
+	kind := marker[2 : len(marker)-1]
+	if _, known := alertTypes[kind]; !known {
+		return nil
+	}
+	if para.Lines().Len() == 1 && para.NextSibling() == nil {
+		return nil
+	}
+
+	// Drop the inline nodes that make up the marker line.
+	for c := para.FirstChild(); c != nil; {
+		next := c.NextSibling()
+		if txt, isText := c.(*ast.Text); !isText || txt.Segment.Start >= first.Stop {
+			break
+		}
+		para.RemoveChild(para, c)
+		c = next
+	}
+
+	alert := &alertNode{AlertType: kind}
+	for c := quote.FirstChild(); c != nil; {
+		next := c.NextSibling()
+		if c != para || para.HasChildren() {
+			alert.AppendChild(alert, c)
+		}
+		c = next
+	}
+	return alert
+}
+
+// alertRenderer renders alertNode as GitHub's markdown-alert markup.
+type alertRenderer struct{}
+
+// RegisterFuncs implements renderer.NodeRenderer.
+func (r *alertRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindAlert, r.renderAlert)
+}
+
+func (r *alertRenderer) renderAlert(
+	w util.BufWriter, _ []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		_, _ = w.WriteString("</div>\n")
+		return ast.WalkContinue, nil
+	}
+	kind := node.(*alertNode).AlertType
+	at := alertTypes[kind]
+	_, _ = w.WriteString(`<div class="markdown-alert markdown-alert-` + kind + `">` + "\n")
+	// The path's fill attribute repeats the title colour (which the
+	// stylesheet's "fill: currentColor" would give it) for renderers that
+	// do not apply CSS inside SVG, such as the PDF engine.
+	_, _ = w.WriteString(`<p class="markdown-alert-title"><svg class="octicon octicon-` + at.icon +
+		` mr-2" viewBox="0 0 16 16" version="1.1" width="16" height="16" aria-hidden="true"><path fill="` +
+		at.color + `" d="` + at.path + `"></path></svg>` + at.title + "</p>\n")
+	return ast.WalkContinue, nil
+}
+
+// Compile-time interface checks.
+var (
+	_ goldmark.Extender     = (*alertExtension)(nil)
+	_ renderer.NodeRenderer = (*alertRenderer)(nil)
+)
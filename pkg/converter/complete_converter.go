@@ -3,6 +3,8 @@ package converter
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/sgaunet/mdtohtml/pkg/heading"
 	"github.com/sgaunet/mdtohtml/pkg/htmldoc"
@@ -14,18 +16,29 @@ type CompleteConverter struct {
 	titleExtractor    heading.TitleExtractor
 	htmlTemplate      htmldoc.HTMLTemplate
 	noCSS             bool
+	selfContained     bool
+	inlineStylesheets bool
+	err               error // returned by Convert, e.g. a failure writing the highlight stylesheet
 }
 
 // NewCompleteConverter creates a new complete converter with all components.
+// An error building the highlight stylesheet is returned by every conversion,
+// rather than producing class-based markup without its stylesheet.
 func NewCompleteConverter(opts Options) *CompleteConverter {
+	additional := opts.AdditionalCSS
+	css, err := HighlightCSS(opts)
+	if css != "" {
+		additional = strings.TrimPrefix(additional+"\n"+css, "\n")
+	}
+
 	var tmpl htmldoc.HTMLTemplate
 	switch {
-	case opts.CSSSource != "" && opts.AdditionalCSS != "":
-		tmpl = htmldoc.NewGitHubTemplateWithCSS(opts.CSSSource + "\n" + opts.AdditionalCSS)
+	case opts.CSSSource != "" && additional != "":
+		tmpl = htmldoc.NewGitHubTemplateWithCSS(opts.CSSSource + "\n" + additional)
 	case opts.CSSSource != "":
 		tmpl = htmldoc.NewGitHubTemplateWithCSS(opts.CSSSource)
-	case opts.AdditionalCSS != "":
-		tmpl = htmldoc.NewGitHubTemplateWithAdditionalCSS(opts.AdditionalCSS)
+	case additional != "":
+		tmpl = htmldoc.NewGitHubTemplateWithAdditionalCSS(additional)
 	default:
 		tmpl = htmldoc.NewGitHubTemplate()
 	}
@@ -35,6 +48,9 @@ func NewCompleteConverter(opts Options) *CompleteConverter {
 		titleExtractor:    heading.NewMarkdownTitleExtractor(),
 		htmlTemplate:      tmpl,
 		noCSS:             opts.NoCSS,
+		selfContained:     opts.SelfContained,
+		inlineStylesheets: opts.InlineStylesheets,
+		err:               err,
 	}
 }
 
@@ -60,9 +76,21 @@ func NewCompleteConverterWithComponents(
 }
 
 // Convert transforms markdown content to complete HTML with title and CSS.
+// Front matter author, description, date, keywords and status are emitted as
+// <meta> tags when the template implements [htmldoc.InfoTemplate].
 func (c *CompleteConverter) Convert(input []byte) ([]byte, error) {
+	return c.ConvertSource(input, "")
+}
+
+// ConvertSource is Convert for input read from inputPath, against whose
+// directory the links checked by Options.MarkdownLinkTarget are resolved.
+func (c *CompleteConverter) ConvertSource(input []byte, inputPath string) ([]byte, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+
 	// Convert markdown to HTML
-	htmlContent, err := c.goldmarkConverter.Convert(input)
+	htmlContent, meta, err := c.goldmarkConverter.convert(input, inputPath)
 	if err != nil {
 		return nil, err
 	}
@@ -71,7 +99,19 @@ func (c *CompleteConverter) Convert(input []byte) ([]byte, error) {
 	title := c.titleExtractor.ExtractTitle(input)
 
 	// Wrap in HTML document
-	html := c.htmlTemplate.Wrap(string(htmlContent), title)
+	var html string
+	if tmpl, ok := c.htmlTemplate.(htmldoc.InfoTemplate); ok {
+		html = tmpl.WrapWithInfo(string(htmlContent), htmldoc.DocumentInfo{
+			Title:       title,
+			Author:      meta.Author(),
+			Description: meta.Description(),
+			Date:        meta.Date(),
+			Keywords:    meta.Keywords(),
+			Status:      meta.Status(),
+		})
+	} else {
+		html = c.htmlTemplate.Wrap(string(htmlContent), title)
+	}
 
 	// Inject CSS unless disabled
 	if !c.noCSS {
@@ -82,6 +122,9 @@ func (c *CompleteConverter) Convert(input []byte) ([]byte, error) {
 }
 
 // ConvertFile reads a markdown file and writes the complete HTML output.
+// Local images and stylesheets are embedded relative to the input file's
+// directory when Options.SelfContained or Options.InlineStylesheets is set;
+// those that cannot be read keep their reference, with a warning on stderr.
 func (c *CompleteConverter) ConvertFile(inputPath, outputPath string) error {
 	input, err := os.ReadFile(inputPath)
 	if err != nil {
@@ -91,10 +134,20 @@ func (c *CompleteConverter) ConvertFile(inputPath, outputPath string) error {
 		return fmt.Errorf("error reading file '%s': %w", inputPath, err)
 	}
 
-	output, err := c.Convert(input)
+	output, err := c.ConvertSource(input, inputPath)
 	if err != nil {
 		return err
 	}
+	if c.selfContained || c.inlineStylesheets {
+		var skipped []string
+		output, skipped, err = embedResources(output, filepath.Dir(inputPath), c.selfContained, c.inlineStylesheets)
+		if err != nil {
+			return fmt.Errorf("embedding resources of '%s': %w", inputPath, err)
+		}
+		for _, note := range skipped {
+			fmt.Fprintf(os.Stderr, "Warning: %s: skipped embedding %s\n", inputPath, note)
+		}
+	}
 
 	const defaultFileMode = 0644
 	if err := os.WriteFile(outputPath, output, defaultFileMode); err != nil {
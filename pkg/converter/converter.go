@@ -2,6 +2,8 @@
 package converter
 
 // Converter defines the interface for markdown to HTML conversion.
+// Implementations must be safe for concurrent use: the batch processor
+// shares a single Converter between its workers.
 type Converter interface {
 	// Convert transforms markdown content to HTML
 	Convert(input []byte) ([]byte, error)
@@ -41,6 +43,52 @@ type Options struct {
 
 	// NoCSS skips CSS injection entirely.
 	NoCSS bool
+
+	// HighlightStyle is the Chroma style used to colour fenced code blocks
+	// (e.g. "github", "monokai"). Empty disables syntax highlighting.
+	HighlightStyle string
+
+	// HighlightCSSClasses emits CSS classes instead of inline style attributes
+	// for highlighted code; the matching stylesheet is appended to the page CSS.
+	// The PDF converter always uses inline styles.
+	HighlightCSSClasses bool
+
+	// HighlightLineNumbers prefixes each line of highlighted code with its number.
+	HighlightLineNumbers bool
+
+	// TOC controls the table of contents generated from the document headings.
+	TOC TOCOptions
+
+	// PageBreakBeforeH1 starts every top-level H1 on a new page, except one
+	// opening the document. Page breaks can also be forced anywhere with a
+	// \pagebreak or <!-- pagebreak --> line, with or without this option.
+	PageBreakBeforeH1 bool
+
+	// MarkdownLinkExt, when set, replaces the extension of relative links to
+	// Markdown files (.md, .markdown) with it, e.g. ".html", so that links
+	// between converted documents keep working. Fragments and query strings
+	// are kept; absolute URLs and paths are left alone. Links written as raw
+	// HTML are not rewritten.
+	MarkdownLinkExt string
+
+	// MarkdownLinkTarget, when set, limits MarkdownLinkExt to the links whose
+	// target it reports true for. It is given the path of the linked file,
+	// resolved against the directory of the input file. Input converted
+	// without a path (Convert) keeps all its links unchanged. Batch
+	// conversion uses it to rewrite only the links to files it converts.
+	MarkdownLinkTarget func(path string) bool
+
+	// SelfContained embeds the local images referenced by <img> elements as
+	// data: URIs, so that the HTML file can be moved or sent on its own.
+	// Sources are resolved relative to the input file, so it only applies
+	// to CompleteConverter.ConvertFile. PDF output ignores it.
+	SelfContained bool
+
+	// InlineStylesheets replaces <link rel="stylesheet"> elements that point
+	// at local CSS files with <style> elements holding the file content, with
+	// the local url() references in it embedded as data: URIs. Like
+	// SelfContained it only applies to CompleteConverter.ConvertFile.
+	InlineStylesheets bool
 }
 
 // DefaultOptions returns the default converter options.
@@ -1,6 +1,7 @@
 package converter_test
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -8,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/sgaunet/mdtohtml/pkg/converter"
+	"github.com/sgaunet/mdtohtml/pkg/frontmatter"
 )
 
 // TestGoldmarkConverter_Convert tests the basic markdown conversion functionality
@@ -113,6 +115,18 @@ func TestCompleteConverter_Convert(t *testing.T) {
 			},
 			options: converter.DefaultOptions(),
 		},
+		{
+			name:  "front matter metadata",
+			input: "---\ntitle: Meta Title\nauthor: Jane Doe\ndescription: Summary\ntags: [a, b]\n---\n# Heading\n",
+			contains: []string{
+				"<title>Meta Title</title>",
+				`<meta name="author" content="Jane Doe">`,
+				`<meta name="description" content="Summary">`,
+				`<meta name="keywords" content="a, b">`,
+				"<h1", "Heading",
+			},
+			options: converter.DefaultOptions(),
+		},
 	}
 
 	for _, tt := range tests {
@@ -133,6 +147,408 @@ func TestCompleteConverter_Convert(t *testing.T) {
 	}
 }
 
+// TestGoldmarkConverter_ConvertWithMetadata tests front matter parsing and stripping
+func TestGoldmarkConverter_ConvertWithMetadata(t *testing.T) {
+	conv := converter.NewGoldmarkConverter(converter.DefaultOptions())
+
+	t.Run("front matter is stripped and returned", func(t *testing.T) {
+		input := "---\ntitle: Doc\nauthor: Someone\n---\n\nBody text.\n"
+		output, meta, err := conv.ConvertWithMetadata([]byte(input))
+		if err != nil {
+			t.Fatalf("ConvertWithMetadata() error = %v", err)
+		}
+		if strings.Contains(string(output), "<hr") || strings.Contains(string(output), "author") {
+			t.Errorf("front matter leaked into output: %s", output)
+		}
+		if !strings.Contains(string(output), "<p>Body text.</p>") {
+			t.Errorf("body missing from output: %s", output)
+		}
+		if meta.Title() != "Doc" || meta.Author() != "Someone" {
+			t.Errorf("unexpected metadata: %v", meta)
+		}
+	})
+
+	t.Run("no front matter", func(t *testing.T) {
+		_, meta, err := conv.ConvertWithMetadata([]byte("# Title\n"))
+		if err != nil {
+			t.Fatalf("ConvertWithMetadata() error = %v", err)
+		}
+		if meta != nil {
+			t.Errorf("expected nil metadata, got %v", meta)
+		}
+	})
+
+	t.Run("invalid front matter", func(t *testing.T) {
+		_, err := conv.Convert([]byte("---\ntitle: [oops\n---\n"))
+		if !errors.Is(err, frontmatter.ErrInvalidFrontMatter) {
+			t.Errorf("Convert() error = %v, want ErrInvalidFrontMatter", err)
+		}
+	})
+}
+
+// TestGoldmarkConverter_Highlighting tests server-side syntax highlighting of fenced code
+func TestGoldmarkConverter_Highlighting(t *testing.T) {
+	input := "```go\nfunc main() {}\n```\n"
+
+	tests := []struct {
+		name        string
+		options     converter.Options
+		contains    []string
+		notContains []string
+	}{
+		{
+			name:        "disabled by default",
+			options:     converter.DefaultOptions(),
+			contains:    []string{`<code class="language-go">`},
+			notContains: []string{"style=", "chroma"},
+		},
+		{
+			name:        "inline styles",
+			options:     converter.Options{HighlightStyle: "github"},
+			contains:    []string{"<pre style=", "<span style=", "func"},
+			notContains: []string{`class="chroma"`},
+		},
+		{
+			name:        "css classes",
+			options:     converter.Options{HighlightStyle: "github", HighlightCSSClasses: true},
+			contains:    []string{`class="chroma"`, `class="kd"`},
+			notContains: []string{"<span style="},
+		},
+		{
+			name:     "line numbers",
+			options:  converter.Options{HighlightStyle: "github", HighlightCSSClasses: true, HighlightLineNumbers: true},
+			contains: []string{`<span class="ln">1</span>`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := converter.NewGoldmarkConverter(tt.options).Convert([]byte(input))
+			if err != nil {
+				t.Fatalf("Convert() error = %v", err)
+			}
+			for _, expected := range tt.contains {
+				if !strings.Contains(string(output), expected) {
+					t.Errorf("Convert() output does not contain %q\nGot: %s", expected, output)
+				}
+			}
+			for _, notExpected := range tt.notContains {
+				if strings.Contains(string(output), notExpected) {
+					t.Errorf("Convert() output should not contain %q\nGot: %s", notExpected, output)
+				}
+			}
+		})
+	}
+}
+
+// TestGoldmarkConverter_TOC tests table of contents generation and placement
+func TestGoldmarkConverter_TOC(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		toc         converter.TOCOptions
+		contains    []string
+		notContains []string
+		tocFirst    bool
+	}{
+		{
+			name:        "disabled by default",
+			input:       "# Title\n\n## Part\n",
+			notContains: []string{`<nav class="toc">`},
+		},
+		{
+			name:  "nested list at top",
+			input: "# Title\n\n## Part *one*\n\n### Detail\n\n## Part two\n",
+			toc:   converter.TOCOptions{Enabled: true},
+			contains: []string{
+				`<li><a href="#title">Title</a>` + "\n<ul>\n" + `<li><a href="#part-one">Part one</a>`,
+				`<li><a href="#detail">Detail</a></li>`,
+				`<li><a href="#part-two">Part two</a></li>`,
+			},
+			tocFirst: true,
+		},
+		{
+			name:        "paragraph placeholder",
+			input:       "# Title\n\nIntro\n\n[TOC]\n\n## Part\n",
+			toc:         converter.TOCOptions{Enabled: true},
+			contains:    []string{"<p>Intro</p>\n<nav class=\"toc\">"},
+			notContains: []string{"[TOC]"},
+		},
+		{
+			name:        "comment placeholder",
+			input:       "# Title\n\nIntro\n\n<!-- toc -->\n\n## Part\n",
+			toc:         converter.TOCOptions{Enabled: true},
+			contains:    []string{"<p>Intro</p>\n<nav class=\"toc\">"},
+			notContains: []string{"<!-- toc -->"},
+		},
+		{
+			name:        "depth range",
+			input:       "# Title\n\n## Part\n\n### Detail\n",
+			toc:         converter.TOCOptions{Enabled: true, MinDepth: 2, MaxDepth: 2},
+			contains:    []string{`<li><a href="#part">Part</a></li>`},
+			notContains: []string{`href="#title"`, `href="#detail"`},
+		},
+		{
+			name:        "placeholder removed without headings",
+			input:       "Intro\n\n[TOC]\n",
+			toc:         converter.TOCOptions{Enabled: true},
+			notContains: []string{"[TOC]", "<nav"},
+		},
+		{
+			name:  "flat entries",
+			input: "# Title\n\n## Part\n",
+			toc:   converter.TOCOptions{Enabled: true, Flat: true},
+			contains: []string{
+				`<div class="toc-entry toc-depth-1"><a href="#title">Title</a></div>`,
+				`<div class="toc-entry toc-depth-2"><a href="#part">Part</a></div>`,
+			},
+			notContains: []string{"<ul>"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := converter.NewGoldmarkConverter(converter.Options{TOC: tt.toc}).Convert([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("Convert() error = %v", err)
+			}
+			for _, expected := range tt.contains {
+				if !strings.Contains(string(output), expected) {
+					t.Errorf("Convert() output does not contain %q\nGot: %s", expected, output)
+				}
+			}
+			for _, notExpected := range tt.notContains {
+				if strings.Contains(string(output), notExpected) {
+					t.Errorf("Convert() output should not contain %q\nGot: %s", notExpected, output)
+				}
+			}
+			if tt.tocFirst && !strings.HasPrefix(string(output), `<nav class="toc">`) {
+				t.Errorf("Convert() output should start with the TOC\nGot: %s", output)
+			}
+		})
+	}
+}
+
+// TestGoldmarkConverter_Alerts tests GitHub-style alert blockquotes
+func TestGoldmarkConverter_Alerts(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		contains    []string
+		notContains []string
+	}{
+		{
+			name:  "note",
+			input: "> [!NOTE]\n> Read *this*.\n",
+			contains: []string{
+				`<div class="markdown-alert markdown-alert-note">`,
+				`<p class="markdown-alert-title"><svg class="octicon octicon-info mr-2"`,
+				`</svg>Note</p>`,
+				"<p>Read <em>this</em>.</p>\n</div>",
+			},
+			notContains: []string{"[!NOTE]", "<blockquote>"},
+		},
+		{
+			name:     "case insensitive marker with separate paragraph",
+			input:    "> [!caution]\n>\n> First.\n>\n> Second.\n",
+			contains: []string{`markdown-alert-caution`, "</svg>Caution</p>\n<p>First.</p>\n<p>Second.</p>"},
+		},
+		{
+			name:     "all types",
+			input:    "> [!TIP]\n> a\n\n> [!IMPORTANT]\n> b\n\n> [!WARNING]\n> c\n",
+			contains: []string{`markdown-alert-tip`, `markdown-alert-important`, `markdown-alert-warning`},
+		},
+		{
+			name:        "unknown type stays a blockquote",
+			input:       "> [!DANGER]\n> text\n",
+			contains:    []string{"<blockquote>", "[!DANGER]"},
+			notContains: []string{"markdown-alert"},
+		},
+		{
+			name:        "marker without content stays a blockquote",
+			input:       "> [!NOTE]\n",
+			contains:    []string{"<blockquote>"},
+			notContains: []string{"markdown-alert"},
+		},
+		{
+			name:        "nested alert stays a blockquote",
+			input:       "- item\n\n  > [!NOTE]\n  > text\n",
+			contains:    []string{"<blockquote>"},
+			notContains: []string{"markdown-alert"},
+		},
+		{
+			name:        "marker must be alone on its line",
+			input:       "> [!NOTE] inline text\n",
+			contains:    []string{"<blockquote>"},
+			notContains: []string{"markdown-alert"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := converter.NewGoldmarkConverter(converter.DefaultOptions()).Convert([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("Convert() error = %v", err)
+			}
+			for _, expected := range tt.contains {
+				if !strings.Contains(string(output), expected) {
+					t.Errorf("Convert() output does not contain %q\nGot: %s", expected, output)
+				}
+			}
+			for _, notExpected := range tt.notContains {
+				if strings.Contains(string(output), notExpected) {
+					t.Errorf("Convert() output should not contain %q\nGot: %s", notExpected, output)
+				}
+			}
+		})
+	}
+}
+
+func TestGoldmarkConverter_PageBreaks(t *testing.T) {
+	const pageBreak = `<div class="page-break" style="page-break-after: always;"></div>`
+	tests := []struct {
+		name     string
+		opts     func(*converter.Options)
+		input    string
+		breaks   int
+		contains []string
+	}{
+		{
+			name:   "backslash marker",
+			input:  "one\n\n\\pagebreak\n\ntwo\n",
+			breaks: 1,
+		},
+		{
+			name:   "comment marker, case insensitive",
+			input:  "one\n\n<!-- PageBreak -->\n\ntwo\n",
+			breaks: 1,
+		},
+		{
+			name:   "comment marker in safe mode",
+			opts:   func(o *converter.Options) { o.SafeMode = true },
+			input:  "one\n\n<!-- pagebreak -->\n\ntwo\n",
+			breaks: 1,
+		},
+		{
+			name:     "marker must be alone in its paragraph",
+			input:    "see \\pagebreak\n",
+			breaks:   0,
+			contains: []string{"<p>see \\pagebreak</p>"},
+		},
+		{
+			name:   "H1 breaks skip the first block and existing breaks",
+			opts:   func(o *converter.Options) { o.PageBreakBeforeH1 = true },
+			input:  "# One\n\n## Sub\n\n# Two\n\n\\pagebreak\n\n# Three\n",
+			breaks: 2,
+			contains: []string{
+				"</h2>\n" + pageBreak + "\n<h1 id=\"two\">",
+				"</h1>\n" + pageBreak + "\n<h1 id=\"three\">",
+			},
+		},
+		{
+			name:   "H1 breaks are off by default",
+			input:  "# One\n\n# Two\n",
+			breaks: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := converter.DefaultOptions()
+			if tt.opts != nil {
+				tt.opts(&opts)
+			}
+			output, err := converter.NewGoldmarkConverter(opts).Convert([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("Convert() error = %v", err)
+			}
+			if got := strings.Count(string(output), pageBreak); got != tt.breaks {
+				t.Errorf("Convert() output has %d page breaks, want %d\nGot: %s", got, tt.breaks, output)
+			}
+			for _, expected := range tt.contains {
+				if !strings.Contains(string(output), expected) {
+					t.Errorf("Convert() output does not contain %q\nGot: %s", expected, output)
+				}
+			}
+		})
+	}
+}
+
+// TestValidateTOC tests table of contents depth validation
+func TestValidateTOC(t *testing.T) {
+	tests := []struct {
+		name    string
+		toc     converter.TOCOptions
+		wantErr bool
+	}{
+		{name: "defaults", toc: converter.TOCOptions{}},
+		{name: "range", toc: converter.TOCOptions{MinDepth: 2, MaxDepth: 3}},
+		{name: "min above max", toc: converter.TOCOptions{MinDepth: 4, MaxDepth: 2}, wantErr: true},
+		{name: "out of range", toc: converter.TOCOptions{MaxDepth: 7}, wantErr: true},
+		{name: "negative", toc: converter.TOCOptions{MinDepth: -1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := converter.ValidateTOC(tt.toc)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateTOC() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, converter.ErrInvalidTOCDepth) {
+				t.Errorf("ValidateTOC() error = %v, want ErrInvalidTOCDepth", err)
+			}
+		})
+	}
+}
+
+// TestCompleteConverter_HighlightCSS tests that the highlight stylesheet is only
+// appended when CSS classes are used
+func TestCompleteConverter_HighlightCSS(t *testing.T) {
+	input := []byte("```go\nfunc main() {}\n```\n")
+
+	classes := converter.DefaultOptions()
+	classes.HighlightStyle = "monokai"
+	classes.HighlightCSSClasses = true
+	output, err := converter.NewCompleteConverter(classes).Convert(input)
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+	if !strings.Contains(string(output), ".chroma") || !strings.Contains(string(output), "octicon") {
+		t.Error("expected both the default CSS and the highlight stylesheet")
+	}
+
+	inline := converter.DefaultOptions()
+	inline.HighlightStyle = "monokai"
+	if css, err := converter.HighlightCSS(inline); err != nil || css != "" {
+		t.Errorf("HighlightCSS() with inline styles = %q, %v, want empty", css, err)
+	}
+
+	css, err := converter.HighlightCSS(classes)
+	if err != nil {
+		t.Fatalf("HighlightCSS() error = %v", err)
+	}
+	if !strings.Contains(css, ".chroma") || !strings.Contains(string(output), css) {
+		t.Error("expected the highlight stylesheet in the output")
+	}
+}
+
+// TestValidateHighlightStyle tests highlight style name validation
+func TestValidateHighlightStyle(t *testing.T) {
+	for _, name := range []string{"", "github", "Monokai", "dracula"} {
+		if err := converter.ValidateHighlightStyle(name); err != nil {
+			t.Errorf("ValidateHighlightStyle(%q) error = %v", name, err)
+		}
+	}
+
+	err := converter.ValidateHighlightStyle("no-such-style")
+	if !errors.Is(err, converter.ErrUnknownHighlightStyle) {
+		t.Errorf("ValidateHighlightStyle() error = %v, want ErrUnknownHighlightStyle", err)
+	}
+	if !strings.Contains(err.Error(), "github") {
+		t.Errorf("error should list available styles, got: %v", err)
+	}
+}
+
 // TestConverter_ConvertFile tests file-based conversion
 func TestConverter_ConvertFile(t *testing.T) {
 	// Create temporary test files
@@ -632,4 +1048,288 @@ This is a benchmark test with **bold text**, *italic text*, and [links](http://e
 			b.Fatalf("Convert() error = %v", err)
 		}
 	}
-}
\ No newline at end of file
+}
+// TestCompleteConverter_SelfContained tests embedding local images and stylesheets
+func TestCompleteConverter_SelfContained(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"img/logo.svg":  `<svg xmlns="http://www.w3.org/2000/svg"/>`,
+		"img/photo.png": "\x89PNG\r\n\x1a\n",
+		"img/noext":     "GIF89a",
+		"css/site.css":  `p { background: url('../img/photo.png'); } h1 { background: url(https://example.com/x.png); }`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	input := "# Doc\n\n<link rel=\"stylesheet\" href=\"css/site.css\">\n\n" +
+		"![logo](img/logo.svg) ![photo](./img/photo.png?v=1 \"Photo\") ![gif](img/noext)\n\n" +
+		"![remote](https://example.com/remote.png)\n\n<IMG SRC=\"img/photo.png\" ALT=\"raw\">\n\n" +
+		"```html\n<img src=\"img/photo.png\">\n```\n"
+	inputPath := filepath.Join(dir, "doc.md")
+	if err := os.WriteFile(inputPath, []byte(input), 0644); err != nil {
+		t.Fatalf("Failed to write input: %v", err)
+	}
+
+	svgURI := "data:image/svg+xml;base64,PHN2ZyB4bWxucz0iaHR0cDovL3d3dy53My5vcmcvMjAwMC9zdmciLz4="
+	pngURI := "data:image/png;base64,iVBORw0KGgo="
+	tests := []struct {
+		name        string
+		options     converter.Options
+		contains    []string
+		notContains []string
+	}{
+		{
+			name:    "images",
+			options: converter.Options{SelfContained: true},
+			contains: []string{
+				`<img src="` + svgURI + `" alt="logo"/>`,
+				`<img src="` + pngURI + `" alt="photo" title="Photo"/>`,
+				`<img src="data:image/gif;base64,R0lGODlh" alt="gif"/>`,
+				`<img src="https://example.com/remote.png" alt="remote" />`,
+				`<img src="` + pngURI + `" alt="raw">`,
+				`&lt;img src=&quot;img/photo.png&quot;&gt;`,
+				`<link rel="stylesheet" href="css/site.css">`,
+			},
+		},
+		{
+			name:    "stylesheets",
+			options: converter.Options{InlineStylesheets: true},
+			contains: []string{
+				`p { background: url("` + pngURI + `"); }`,
+				`url(https://example.com/x.png)`,
+				`<img src="img/logo.svg" alt="logo" />`,
+			},
+			notContains: []string{`<link rel="stylesheet"`},
+		},
+		{
+			name:     "disabled",
+			options:  converter.DefaultOptions(),
+			contains: []string{`<img src="img/logo.svg" alt="logo" />`, `<link rel="stylesheet" href="css/site.css">`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputPath := filepath.Join(dir, tt.name+".html")
+			if err := converter.NewCompleteConverter(tt.options).ConvertFile(inputPath, outputPath); err != nil {
+				t.Fatalf("ConvertFile() error = %v", err)
+			}
+			output, err := os.ReadFile(outputPath)
+			if err != nil {
+				t.Fatalf("Failed to read output: %v", err)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(string(output), want) {
+					t.Errorf("Output missing %q", want)
+				}
+			}
+			for _, unwanted := range tt.notContains {
+				if strings.Contains(string(output), unwanted) {
+					t.Errorf("Output unexpectedly contains %q", unwanted)
+				}
+			}
+		})
+	}
+
+	t.Run("missing files", func(t *testing.T) {
+		missingPath := filepath.Join(dir, "missing.md")
+		input := "<link rel=\"stylesheet\" href=\"css/gone.css\">\n\n![gone](img/gone.png) ![logo](img/logo.svg)\n"
+		if err := os.WriteFile(missingPath, []byte(input), 0644); err != nil {
+			t.Fatalf("Failed to write input: %v", err)
+		}
+		conv := converter.NewCompleteConverter(converter.Options{SelfContained: true, InlineStylesheets: true})
+		outputPath := filepath.Join(dir, "missing.html")
+		if err := conv.ConvertFile(missingPath, outputPath); err != nil {
+			t.Fatalf("ConvertFile() error = %v", err)
+		}
+		output, err := os.ReadFile(outputPath)
+		if err != nil {
+			t.Fatalf("Failed to read output: %v", err)
+		}
+		for _, want := range []string{
+			`<link rel="stylesheet" href="css/gone.css">`,
+			`<img src="img/gone.png" alt="gone" />`,
+			`<img src="` + svgURI + `" alt="logo"/>`,
+		} {
+			if !strings.Contains(string(output), want) {
+				t.Errorf("Output missing %q", want)
+			}
+		}
+	})
+}
+
+// TestCompleteConverter_SelfContained_OutsideInputDir tests that files outside
+// the input file's directory are embedded too
+func TestCompleteConverter_SelfContained_OutsideInputDir(t *testing.T) {
+	dir := t.TempDir()
+	secret := filepath.Join(dir, "secret.png")
+	if err := os.WriteFile(secret, []byte("\x89PNG\r\n\x1a\n"), 0644); err != nil {
+		t.Fatalf("Failed to write secret: %v", err)
+	}
+	docDir := filepath.Join(dir, "doc", "css")
+	if err := os.MkdirAll(docDir, 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	css := `p { background: url('../../secret.png'); }`
+	if err := os.WriteFile(filepath.Join(docDir, "site.css"), []byte(css), 0644); err != nil {
+		t.Fatalf("Failed to write stylesheet: %v", err)
+	}
+	absolute := filepath.ToSlash(secret)
+	input := "<link rel=\"stylesheet\" href=\"css/site.css\">\n\n" +
+		"![abs](" + absolute + ") ![up](../secret.png) ![deep](css/../../secret.png)\n"
+	inputPath := filepath.Join(dir, "doc", "doc.md")
+	if err := os.WriteFile(inputPath, []byte(input), 0644); err != nil {
+		t.Fatalf("Failed to write input: %v", err)
+	}
+
+	opts := converter.DefaultOptions()
+	opts.SelfContained = true
+	opts.InlineStylesheets = true
+	outputPath := filepath.Join(dir, "doc", "doc.html")
+	if err := converter.NewCompleteConverter(opts).ConvertFile(inputPath, outputPath); err != nil {
+		t.Fatalf("ConvertFile() error = %v", err)
+	}
+	output, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+	for _, ref := range []string{absolute, "../secret.png", "css/../../secret.png", "../../secret.png"} {
+		if strings.Contains(string(output), ref) {
+			t.Errorf("Output still references %q", ref)
+		}
+	}
+	if n := strings.Count(string(output), "data:image/png;base64,"); n != 4 {
+		t.Errorf("Output embeds %d images, want 4", n)
+	}
+}
+
+// TestGoldmarkConverter_MarkdownLinkExt tests rewriting of links to Markdown files
+func TestGoldmarkConverter_MarkdownLinkExt(t *testing.T) {
+	tests := []struct {
+		name string
+		link string
+		ext  string
+		want string
+	}{
+		{name: "relative link", link: "setup.md", ext: ".html", want: "setup.html"},
+		{name: "parent directory", link: "../guide/setup.md", ext: ".html", want: "../guide/setup.html"},
+		{name: "fragment kept", link: "guide/setup.md#install", ext: ".html", want: "guide/setup.html#install"},
+		{name: "query kept", link: "setup.md?v=2#top", ext: ".pdf", want: "setup.pdf?v=2#top"},
+		{name: "markdown extension", link: "notes.markdown", ext: ".html", want: "notes.html"},
+		{name: "uppercase extension", link: "README.MD", ext: ".html", want: "README.html"},
+		{name: "absolute URL", link: "https://example.com/setup.md", ext: ".html", want: "https://example.com/setup.md"},
+		{name: "protocol-relative URL", link: "//example.com/setup.md", ext: ".html", want: "//example.com/setup.md"},
+		{name: "absolute path", link: "/docs/setup.md", ext: ".html", want: "/docs/setup.md"},
+		{name: "fragment only", link: "#setup.md", ext: ".html", want: "#setup.md"},
+		{name: "other file", link: "image.png", ext: ".html", want: "image.png"},
+		{name: "md in directory name", link: "docs.md/file.txt", ext: ".html", want: "docs.md/file.txt"},
+		{name: "disabled", link: "setup.md", ext: "", want: "setup.md"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conv := converter.NewGoldmarkConverter(converter.Options{MarkdownLinkExt: tt.ext})
+			output, err := conv.Convert([]byte("[inline](" + tt.link + ") [ref]\n\n[ref]: " + tt.link + "\n"))
+			if err != nil {
+				t.Fatalf("Convert() error = %v", err)
+			}
+			want := `href="` + tt.want + `"`
+			if got := strings.Count(string(output), want); got != 2 {
+				t.Errorf("Convert() = %s, want two links with %s", output, want)
+			}
+		})
+	}
+}
+
+// TestCompleteConverter_MarkdownLinkTarget tests that only links to accepted
+// targets are rewritten
+func TestCompleteConverter_MarkdownLinkTarget(t *testing.T) {
+	dir := t.TempDir()
+	inputPath := filepath.Join(dir, "docs", "index.md")
+	input := "[a](guide/setup.md#install) [b](notes.markdown) [c](../other.md) [d](my%20file.md)\n"
+	if err := os.MkdirAll(filepath.Dir(inputPath), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(inputPath, []byte(input), 0644); err != nil {
+		t.Fatalf("Failed to create input: %v", err)
+	}
+	accepted := map[string]bool{
+		filepath.Join(dir, "docs", "guide", "setup.md"): true,
+		filepath.Join(dir, "docs", "my file.md"):        true,
+	}
+	conv := converter.NewCompleteConverter(converter.Options{
+		NoCSS:              true,
+		MarkdownLinkExt:    ".html",
+		MarkdownLinkTarget: func(path string) bool { return accepted[path] },
+	})
+
+	outputPath := filepath.Join(dir, "index.html")
+	if err := conv.ConvertFile(inputPath, outputPath); err != nil {
+		t.Fatalf("ConvertFile() error = %v", err)
+	}
+	output, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+	for _, want := range []string{
+		`href="guide/setup.html#install"`, `href="notes.markdown"`, `href="../other.md"`, `href="my%20file.html"`,
+	} {
+		if !strings.Contains(string(output), want) {
+			t.Errorf("ConvertFile() output missing %s:\n%s", want, output)
+		}
+	}
+
+	// Without a file there is nothing to resolve the links against.
+	output, err = conv.Convert([]byte(input))
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+	if !strings.Contains(string(output), `href="guide/setup.md#install"`) {
+		t.Errorf("Convert() rewrote a link without an input path:\n%s", output)
+	}
+}
+
+// TestReferences tests link and image discovery in Markdown documents
+func TestReferences(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "links and images",
+			input: "[a](a.md) ![b](img/b.png \"B\") [c][ref] <https://example.com>\n\n[ref]: files/c.pdf\n",
+			want:  []string{"a.md", "img/b.png", "files/c.pdf", "https://example.com"},
+		},
+		{
+			name:  "raw HTML",
+			input: "<video src=\"clip.mp4\" poster=\"poster.jpg\"></video>\n\ntext <a href=\"doc.pdf\">doc</a> <img src=\"x.svg\">\n",
+			want:  []string{"clip.mp4", "poster.jpg", "doc.pdf", "x.svg"},
+		},
+		{
+			name:  "duplicates and front matter",
+			input: "---\nimage: cover.png\n---\n![x](a.png) ![y](a.png)\n",
+			want:  []string{"a.png"},
+		},
+		{
+			name:  "code is ignored",
+			input: "`![x](inline.png)`\n\n```\n![y](fenced.png)\n```\n",
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := converter.References([]byte(tt.input))
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("References() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
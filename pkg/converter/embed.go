@@ -0,0 +1,216 @@
+package converter
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// embedMIMETypes maps lowercased file extensions of images and web fonts to
+// their MIME types. Unlisted extensions fall back to content sniffing, which
+// cannot recognise SVG.
+var embedMIMETypes = map[string]string{
+	".apng":  "image/apng",
+	".avif":  "image/avif",
+	".bmp":   "image/bmp",
+	".gif":   "image/gif",
+	".ico":   "image/x-icon",
+	".jpeg":  "image/jpeg",
+	".jpg":   "image/jpeg",
+	".png":   "image/png",
+	".svg":   "image/svg+xml",
+	".tif":   "image/tiff",
+	".tiff":  "image/tiff",
+	".webp":  "image/webp",
+	".otf":   "font/otf",
+	".ttf":   "font/ttf",
+	".woff":  "font/woff",
+	".woff2": "font/woff2",
+}
+
+// cssURLPattern matches url(...) references in a stylesheet. The first
+// non-empty group holds the reference without quotes.
+var cssURLPattern = regexp.MustCompile(`url\(\s*(?:"([^"]*)"|'([^']*)'|([^)'"\s]*))\s*\)`)
+
+// embedResources rewrites page so that it no longer depends on local files:
+// with images set, <img> sources become data: URIs; with stylesheets set,
+// <link rel="stylesheet"> elements become <style> elements holding the file's
+// CSS, whose own url() references are embedded as well. Relative references
+// are resolved against baseDir, those of stylesheets against their own
+// directory, and absolute paths are used as they are; remote and data:
+// references are kept.
+//
+// A referenced file that cannot be read keeps its reference, like a broken
+// link in HTML that is not self-contained; the returned notes describe each
+// one. Only the rewritten tags change; the rest of page is copied byte for
+// byte.
+func embedResources(page []byte, baseDir string, images, stylesheets bool) ([]byte, []string, error) {
+	e := &embedder{baseDir: baseDir}
+
+	var out bytes.Buffer
+	out.Grow(len(page))
+	z := html.NewTokenizer(bytes.NewReader(page))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			if err := z.Err(); err != io.EOF {
+				return nil, nil, fmt.Errorf("%w: parsing HTML: %w", ErrEmbedResource, err)
+			}
+			return out.Bytes(), e.skipped, nil
+		}
+		if tt != html.StartTagToken && tt != html.SelfClosingTagToken {
+			out.Write(z.Raw())
+			continue
+		}
+
+		// Token lowercases names in the tokenizer's buffer, so keep a copy
+		// of the original bytes first.
+		raw := bytes.Clone(z.Raw())
+		tok := z.Token()
+		var rewritten string
+		switch {
+		case images && tok.Data == "img":
+			rewritten = e.image(tok)
+		case stylesheets && tok.Data == "link":
+			rewritten = e.stylesheet(tok)
+		}
+		if rewritten == "" {
+			out.Write(raw)
+		} else {
+			out.WriteString(rewritten)
+		}
+	}
+}
+
+// embedder reads the files embedded by embedResources and notes the
+// references it had to skip.
+type embedder struct {
+	baseDir string
+	skipped []string
+}
+
+// skip notes that the file ref refers to could not be embedded.
+func (e *embedder) skip(ref string, err error) {
+	e.skipped = append(e.skipped, fmt.Sprintf("'%s': %v", ref, err))
+}
+
+// image returns tok with its src replaced by a data: URI, or "" when the
+// source is not a readable local file.
+func (e *embedder) image(tok html.Token) string {
+	for i, a := range tok.Attr {
+		if a.Key != "src" {
+			continue
+		}
+		path, ok := localPath(a.Val, e.baseDir)
+		if !ok {
+			return ""
+		}
+		uri, err := dataURI(path)
+		if err != nil {
+			e.skip(a.Val, err)
+			return ""
+		}
+		tok.Attr[i].Val = uri
+		return tok.String()
+	}
+	return ""
+}
+
+// stylesheet returns a <style> element with the content of the local
+// stylesheet tok links to, or "" when tok is not such a link.
+func (e *embedder) stylesheet(tok html.Token) string {
+	var rel, href, media string
+	for _, a := range tok.Attr {
+		switch a.Key {
+		case "rel":
+			rel = a.Val
+		case "href":
+			href = a.Val
+		case "media":
+			media = a.Val
+		}
+	}
+	if !strings.EqualFold(strings.TrimSpace(rel), "stylesheet") {
+		return ""
+	}
+	path, ok := localPath(href, e.baseDir)
+	if !ok {
+		return ""
+	}
+	data, err := os.ReadFile(path) //nolint:gosec // reading referenced assets is the point
+	if err != nil {
+		e.skip(href, err)
+		return ""
+	}
+	css := e.cssURLs(string(data), filepath.Dir(path))
+
+	open := "<style>"
+	if media != "" {
+		open = `<style media="` + html.EscapeString(media) + `">`
+	}
+	// A literal "</style" would end the element early.
+	css = strings.ReplaceAll(css, "</style", `<\/style`)
+	return open + "\n" + css + "\n</style>"
+}
+
+// cssURLs replaces the url() references of css to readable local files with
+// data: URIs. dir is the directory of the stylesheet.
+func (e *embedder) cssURLs(css, dir string) string {
+	return cssURLPattern.ReplaceAllStringFunc(css, func(m string) string {
+		groups := cssURLPattern.FindStringSubmatch(m)
+		ref := groups[1] + groups[2] + groups[3]
+		path, ok := localPath(ref, dir)
+		if !ok {
+			return m
+		}
+		uri, err := dataURI(path)
+		if err != nil {
+			e.skip(ref, err)
+			return m
+		}
+		return `url("` + uri + `")`
+	})
+}
+
+// localPath resolves ref to a file path when it refers to a local file:
+// relative references are joined to dir, absolute paths are used as they
+// are, and query strings and fragments are dropped. It reports false for
+// empty references, fragments, data: URIs and anything with a scheme or
+// host.
+func localPath(ref, dir string) (string, bool) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" || strings.HasPrefix(ref, "#") {
+		return "", false
+	}
+	u, err := url.Parse(ref)
+	if err != nil || u.Scheme != "" || u.Host != "" || u.Path == "" {
+		return "", false
+	}
+	p := filepath.FromSlash(u.Path)
+	if filepath.IsAbs(p) {
+		return p, true
+	}
+	return filepath.Join(dir, p), true
+}
+
+// dataURI reads path and returns it as a base64 data: URI.
+func dataURI(path string) (string, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // reading referenced assets is the point
+	if err != nil {
+		return "", err //nolint:wrapcheck // noted by skip with its reference
+	}
+	mimeType, ok := embedMIMETypes[strings.ToLower(filepath.Ext(path))]
+	if !ok {
+		mimeType = http.DetectContentType(data)
+	}
+	return "data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(data), nil
+}
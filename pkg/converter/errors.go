@@ -0,0 +1,14 @@
+package converter
+
+import "errors"
+
+// ErrUnknownHighlightStyle is returned when a syntax highlighting style name is not recognised.
+var ErrUnknownHighlightStyle = errors.New("unknown highlight style")
+
+// ErrInvalidTOCDepth is returned when the table of contents depth range is
+// outside 1-6 or its minimum exceeds its maximum.
+var ErrInvalidTOCDepth = errors.New("invalid table of contents depth")
+
+// ErrEmbedResource is returned when a local image or stylesheet cannot be
+// embedded into self-contained HTML output.
+var ErrEmbedResource = errors.New("cannot embed resource")
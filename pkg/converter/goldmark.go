@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/sgaunet/mdtohtml/pkg/frontmatter"
 	"github.com/yuin/goldmark"
 	"github.com/yuin/goldmark/extension"
 	"github.com/yuin/goldmark/parser"
@@ -23,12 +24,26 @@ func NewGoldmarkConverter(opts Options) *GoldmarkConverter {
 		extension.GFM,
 		extension.DefinitionList,
 		extension.Footnote,
+		&alertExtension{},
+		&pageBreakExtension{beforeH1: opts.PageBreakBeforeH1},
 	}
 
 	if opts.SmartPunctuation || opts.LaTeXDashes || opts.Fractions {
 		extensions = append(extensions, extension.Typographer)
 	}
 
+	if opts.HighlightStyle != "" {
+		extensions = append(extensions, highlightExtension(opts))
+	}
+
+	if opts.TOC.Enabled {
+		extensions = append(extensions, &tocExtension{options: opts.TOC})
+	}
+
+	if opts.MarkdownLinkExt != "" {
+		extensions = append(extensions, &linkRewriteExtension{ext: opts.MarkdownLinkExt, target: opts.MarkdownLinkTarget})
+	}
+
 	var md goldmark.Markdown
 	if opts.SafeMode {
 		md = goldmark.New(
@@ -59,13 +74,34 @@ func NewGoldmarkConverter(opts Options) *GoldmarkConverter {
 	}
 }
 
-// Convert transforms markdown content to HTML.
+// Convert transforms markdown content to HTML. Front matter is stripped
+// from the output; use ConvertWithMetadata to retrieve it.
 func (c *GoldmarkConverter) Convert(input []byte) ([]byte, error) {
+	output, _, err := c.ConvertWithMetadata(input)
+	return output, err
+}
+
+// ConvertWithMetadata transforms markdown content to HTML and returns the
+// decoded YAML or TOML front matter, which is nil when the input has none.
+func (c *GoldmarkConverter) ConvertWithMetadata(input []byte) ([]byte, frontmatter.Metadata, error) {
+	return c.convert(input, "")
+}
+
+// convert is ConvertWithMetadata for input read from inputPath, which is
+// empty when input does not come from a file.
+func (c *GoldmarkConverter) convert(input []byte, inputPath string) ([]byte, frontmatter.Metadata, error) {
+	meta, body, err := frontmatter.Split(input)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error converting markdown: %w", err)
+	}
+
+	pc := parser.NewContext()
+	pc.Set(sourcePathKey, inputPath)
 	var buf bytes.Buffer
-	if err := c.md.Convert(input, &buf); err != nil {
-		return nil, fmt.Errorf("error converting markdown: %w", err)
+	if err := c.md.Convert(body, &buf, parser.WithContext(pc)); err != nil {
+		return nil, nil, fmt.Errorf("error converting markdown: %w", err)
 	}
-	return buf.Bytes(), nil
+	return buf.Bytes(), meta, nil
 }
 
 // ConvertFile reads a markdown file and writes the HTML output.
@@ -78,7 +114,7 @@ func (c *GoldmarkConverter) ConvertFile(inputPath, outputPath string) error {
 		return fmt.Errorf("error reading file '%s': %w", inputPath, err)
 	}
 
-	output, err := c.Convert(input)
+	output, _, err := c.convert(input, inputPath)
 	if err != nil {
 		return err
 	}
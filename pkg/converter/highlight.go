@@ -0,0 +1,61 @@
+package converter
+
+import (
+	"fmt"
+	"strings"
+
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/yuin/goldmark"
+	highlighting "github.com/yuin/goldmark-highlighting/v2"
+)
+
+// HighlightStyles returns the names of the available syntax highlighting
+// styles, sorted alphabetically.
+func HighlightStyles() []string {
+	return styles.Names()
+}
+
+// ValidateHighlightStyle checks that name is a known highlighting style.
+// The empty string (highlighting disabled) is valid.
+func ValidateHighlightStyle(name string) error {
+	if name == "" {
+		return nil
+	}
+	if _, ok := styles.Registry[strings.ToLower(name)]; !ok {
+		return fmt.Errorf("%w: %s (available: %s)",
+			ErrUnknownHighlightStyle, name, strings.Join(HighlightStyles(), ", "))
+	}
+	return nil
+}
+
+// HighlightCSS returns the stylesheet that colours highlighted code when
+// opts.HighlightCSSClasses is set. It returns "" when highlighting is disabled
+// or inline styles are used.
+func HighlightCSS(opts Options) (string, error) {
+	if opts.HighlightStyle == "" || !opts.HighlightCSSClasses {
+		return "", nil
+	}
+	var b strings.Builder
+	if err := chromahtml.New(highlightFormatOptions(opts)...).WriteCSS(&b, styles.Get(opts.HighlightStyle)); err != nil {
+		return "", fmt.Errorf("writing the %s highlight stylesheet: %w", opts.HighlightStyle, err)
+	}
+	return b.String(), nil
+}
+
+// highlightExtension returns the goldmark extension that colours fenced code
+// blocks with Chroma. Highlighting runs entirely offline at conversion time.
+func highlightExtension(opts Options) goldmark.Extender {
+	return highlighting.NewHighlighting(
+		highlighting.WithStyle(opts.HighlightStyle),
+		highlighting.WithFormatOptions(highlightFormatOptions(opts)...),
+	)
+}
+
+// highlightFormatOptions maps Options onto Chroma HTML formatter options.
+func highlightFormatOptions(opts Options) []chromahtml.Option {
+	return []chromahtml.Option{
+		chromahtml.WithClasses(opts.HighlightCSSClasses),
+		chromahtml.WithLineNumbers(opts.HighlightLineNumbers),
+	}
+}
@@ -0,0 +1,121 @@
+package converter
+
+import (
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// markdownExts are the extensions of the link targets rewritten by
+// linkRewriteExtension.
+var markdownExts = []string{".md", ".markdown"}
+
+// sourcePathKey holds the path of the Markdown file being converted, if
+// any, in the parser.Context.
+var sourcePathKey = parser.NewContextKey()
+
+// linkRewriteExtension points relative links to Markdown files at the
+// converted file instead, e.g. "guide/setup.md#install" becomes
+// "guide/setup.html#install" when ext is ".html". Batch conversion mirrors
+// the input tree, so changing the extension yields the path that
+// processor.GetOutputPathExt produces for the target. When target is set,
+// only links to files it reports true for are rewritten.
+type linkRewriteExtension struct {
+	ext    string
+	target func(path string) bool
+}
+
+// Extend implements goldmark.Extender.
+func (e *linkRewriteExtension) Extend(m goldmark.Markdown) {
+	const transformerPriority = 800
+	m.Parser().AddOptions(parser.WithASTTransformers(
+		util.Prioritized(&linkRewriteTransformer{ext: e.ext, target: e.target}, transformerPriority),
+	))
+}
+
+// linkRewriteTransformer rewrites the destinations of ast.Link nodes.
+type linkRewriteTransformer struct {
+	ext    string
+	target func(path string) bool
+}
+
+// Transform implements parser.ASTTransformer.
+func (t *linkRewriteTransformer) Transform(doc *ast.Document, _ text.Reader, pc parser.Context) {
+	source, _ := pc.Get(sourcePathKey).(string)
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if link, ok := n.(*ast.Link); ok && entering {
+			dest := string(link.Destination)
+			if rewritten := rewriteMarkdownLink(dest, t.ext); rewritten != dest && t.isTarget(source, dest) {
+				link.Destination = []byte(rewritten)
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+}
+
+// isTarget reports whether the link dest, found in the file at source, may
+// be rewritten. Without a target function every link may; with one, source
+// must be known and the linked file must be accepted by it.
+func (t *linkRewriteTransformer) isTarget(source, dest string) bool {
+	if t.target == nil {
+		return true
+	}
+	if source == "" {
+		return false
+	}
+	u, err := url.Parse(dest)
+	if err != nil {
+		return false
+	}
+	return t.target(filepath.Join(filepath.Dir(source), filepath.FromSlash(u.Path)))
+}
+
+// rewriteMarkdownLink replaces the extension of dest with ext when dest is a
+// relative link to a Markdown file. The query string and fragment are kept.
+// URLs with a scheme, protocol-relative URLs, absolute paths and fragment-only
+// links are returned unchanged.
+func rewriteMarkdownLink(dest, ext string) string {
+	if dest == "" || strings.HasPrefix(dest, "/") || strings.HasPrefix(dest, "#") || hasScheme(dest) {
+		return dest
+	}
+	p, rest := dest, ""
+	if i := strings.IndexAny(dest, "?#"); i >= 0 {
+		p, rest = dest[:i], dest[i:]
+	}
+	old := path.Ext(p)
+	for _, mdExt := range markdownExts {
+		if strings.EqualFold(old, mdExt) {
+			return strings.TrimSuffix(p, old) + ext + rest
+		}
+	}
+	return dest
+}
+
+// hasScheme reports whether ref starts with a URL scheme such as "https:" or
+// "mailto:".
+func hasScheme(ref string) bool {
+	for i, c := range ref {
+		switch {
+		case c == ':':
+			return i > 0
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z':
+		case '0' <= c && c <= '9', c == '+', c == '-', c == '.':
+			if i == 0 {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return false
+}
+
+// Compile-time interface check.
+var _ goldmark.Extender = (*linkRewriteExtension)(nil)
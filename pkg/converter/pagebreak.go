@@ -0,0 +1,115 @@
+package converter
+
+import (
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// pageBreakMarkers are the paragraph texts and HTML comments (compared
+// case-insensitively) that force a page break.
+var pageBreakMarkers = []string{`\pagebreak`, "<!-- pagebreak -->"}
+
+// KindPageBreak is the ast.NodeKind of a forced page break.
+var KindPageBreak = ast.NewNodeKind("PageBreak")
+
+// pageBreakNode is a forced page break.
+type pageBreakNode struct {
+	ast.BaseBlock
+}
+
+// Kind implements ast.Node.
+func (n *pageBreakNode) Kind() ast.NodeKind {
+	return KindPageBreak
+}
+
+// Dump implements ast.Node.
+func (n *pageBreakNode) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, nil, nil)
+}
+
+// pageBreakExtension turns page break markers into an element that starts
+// a new page in the PDF and when printing. Being generated by the
+// converter rather than written as raw HTML, it survives safe mode.
+type pageBreakExtension struct {
+	beforeH1 bool
+}
+
+// Extend implements goldmark.Extender.
+func (e *pageBreakExtension) Extend(m goldmark.Markdown) {
+	// After the table of contents, so that a document opening with it
+	// gets a break between the contents and its first H1.
+	const transformerPriority, rendererPriority = 1100, 500
+	m.Parser().AddOptions(parser.WithASTTransformers(
+		util.Prioritized(&pageBreakTransformer{beforeH1: e.beforeH1}, transformerPriority),
+	))
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(&pageBreakRenderer{}, rendererPriority),
+	))
+}
+
+// pageBreakTransformer replaces the paragraphs and HTML blocks holding
+// only a page break marker with pageBreakNodes. With beforeH1 set, it also
+// inserts one before every top-level H1 that does not open the document.
+type pageBreakTransformer struct {
+	beforeH1 bool
+}
+
+// Transform implements parser.ASTTransformer.
+func (t *pageBreakTransformer) Transform(doc *ast.Document, reader text.Reader, _ parser.Context) {
+	source := reader.Source()
+	var markers []ast.Node
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n.(type) {
+		case *ast.Paragraph, *ast.HTMLBlock:
+			if isPlaceholder(n, source, pageBreakMarkers) {
+				markers = append(markers, n)
+			}
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	for _, n := range markers {
+		n.Parent().ReplaceChild(n.Parent(), n, &pageBreakNode{})
+	}
+
+	if !t.beforeH1 {
+		return
+	}
+	for n := doc.FirstChild(); n != nil; n = n.NextSibling() {
+		h, ok := n.(*ast.Heading)
+		if !ok || h.Level != 1 || h.PreviousSibling() == nil || h.PreviousSibling().Kind() == KindPageBreak {
+			continue
+		}
+		doc.InsertBefore(doc, h, &pageBreakNode{})
+	}
+}
+
+// pageBreakRenderer renders pageBreakNode as an empty block that breaks
+// the page after it.
+type pageBreakRenderer struct{}
+
+// RegisterFuncs implements renderer.NodeRenderer.
+func (r *pageBreakRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindPageBreak, r.renderPageBreak)
+}
+
+func (r *pageBreakRenderer) renderPageBreak(
+	w util.BufWriter, _ []byte, _ ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString(`<div class="page-break" style="page-break-after: always;"></div>` + "\n")
+	}
+	return ast.WalkContinue, nil
+}
+
+// Compile-time interface checks.
+var (
+	_ goldmark.Extender     = (*pageBreakExtension)(nil)
+	_ renderer.NodeRenderer = (*pageBreakRenderer)(nil)
+)
@@ -0,0 +1,88 @@
+package converter
+
+import (
+	"bytes"
+	"slices"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/text"
+	"golang.org/x/net/html"
+
+	"github.com/sgaunet/mdtohtml/pkg/frontmatter"
+)
+
+// referenceParser parses documents for References. Rendering options do not
+// change which links a document contains, so one parser serves every caller.
+var referenceParser = goldmark.New(goldmark.WithExtensions(
+	extension.GFM,
+	extension.DefinitionList,
+	extension.Footnote,
+)).Parser()
+
+// referenceAttrs are the HTML attributes whose values References reports.
+var referenceAttrs = []string{"src", "href", "poster"}
+
+// References returns the destinations of the links and images in a Markdown
+// document, including src, href and poster attributes of raw HTML, in
+// document order and without duplicates. Front matter is skipped. The
+// destinations are returned as written; callers decide which are local.
+func References(input []byte) []string {
+	_, body, err := frontmatter.Split(input)
+	if err != nil {
+		body = input
+	}
+	doc := referenceParser.Parse(text.NewReader(body))
+
+	var refs []string
+	add := func(ref string) {
+		if ref != "" && !slices.Contains(refs, ref) {
+			refs = append(refs, ref)
+		}
+	}
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n := n.(type) {
+		case *ast.Link:
+			add(string(n.Destination))
+		case *ast.Image:
+			add(string(n.Destination))
+		case *ast.AutoLink:
+			if n.AutoLinkType == ast.AutoLinkURL {
+				add(string(n.URL(body)))
+			}
+		case *ast.RawHTML:
+			for _, ref := range htmlReferences(n.Segments.Value(body)) {
+				add(ref)
+			}
+		case *ast.HTMLBlock:
+			for _, ref := range htmlReferences(n.Lines().Value(body)) {
+				add(ref)
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+	return refs
+}
+
+// htmlReferences returns the referenceAttrs values of the tags in fragment.
+func htmlReferences(fragment []byte) []string {
+	var refs []string
+	z := html.NewTokenizer(bytes.NewReader(fragment))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return refs
+		case html.StartTagToken, html.SelfClosingTagToken:
+			for _, a := range z.Token().Attr {
+				if slices.Contains(referenceAttrs, a.Key) {
+					refs = append(refs, a.Val)
+				}
+			}
+		default:
+		}
+	}
+}
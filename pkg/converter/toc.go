@@ -0,0 +1,340 @@
+package converter
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// Heading depth bounds applied when TOCOptions leaves them unset.
+const (
+	defaultTOCMinDepth = 1
+	defaultTOCMaxDepth = 6
+)
+
+// TOCOptions configures the generated table of contents.
+type TOCOptions struct {
+	// Enabled turns on table of contents generation.
+	Enabled bool
+
+	// MinDepth is the shallowest heading level listed (1 = H1).
+	// Zero defaults to 1.
+	MinDepth int
+
+	// MaxDepth is the deepest heading level listed (6 = H6).
+	// Zero defaults to 6.
+	MaxDepth int
+
+	// Flat renders each entry as a <div class="toc-entry toc-depth-N">
+	// instead of nesting <ul> lists. The PDF converter sets it because folio
+	// only resolves in-document links on block-level anchors.
+	Flat bool
+}
+
+// depthRange returns the effective heading level bounds.
+func (o TOCOptions) depthRange() (int, int) {
+	lo, hi := o.MinDepth, o.MaxDepth
+	if lo < 1 {
+		lo = defaultTOCMinDepth
+	}
+	if hi < 1 {
+		hi = defaultTOCMaxDepth
+	}
+	return lo, hi
+}
+
+// ValidateTOC checks that the depth range in o is usable. Zero depths are
+// valid and select the defaults.
+func ValidateTOC(o TOCOptions) error {
+	if o.MinDepth < 0 || o.MinDepth > defaultTOCMaxDepth ||
+		o.MaxDepth < 0 || o.MaxDepth > defaultTOCMaxDepth {
+		return fmt.Errorf("%w: depths must be between %d and %d",
+			ErrInvalidTOCDepth, defaultTOCMinDepth, defaultTOCMaxDepth)
+	}
+	if lo, hi := o.depthRange(); lo > hi {
+		return fmt.Errorf("%w: minimum %d is greater than maximum %d", ErrInvalidTOCDepth, lo, hi)
+	}
+	return nil
+}
+
+// tocPlaceholders are the paragraph texts and HTML comments (compared
+// case-insensitively) that mark where the table of contents goes.
+var tocPlaceholders = []string{"[toc]", "<!-- toc -->"}
+
+// KindTOC is the ast.NodeKind of the generated table of contents block.
+var KindTOC = ast.NewNodeKind("TOC")
+
+// KindTOCEntry is the ast.NodeKind of a table of contents entry in flat mode.
+var KindTOCEntry = ast.NewNodeKind("TOCEntry")
+
+// tocNode is the block that wraps the generated nested heading list.
+type tocNode struct {
+	ast.BaseBlock
+}
+
+// Kind implements ast.Node.
+func (n *tocNode) Kind() ast.NodeKind {
+	return KindTOC
+}
+
+// Dump implements ast.Node.
+func (n *tocNode) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, nil, nil)
+}
+
+// tocEntryNode is a single table of contents entry when TOCOptions.Flat is
+// set. Depth is its nesting depth, starting at 1.
+type tocEntryNode struct {
+	ast.BaseBlock
+	Depth int
+}
+
+// Kind implements ast.Node.
+func (n *tocEntryNode) Kind() ast.NodeKind {
+	return KindTOCEntry
+}
+
+// Dump implements ast.Node.
+func (n *tocEntryNode) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Depth": strconv.Itoa(n.Depth)}, nil)
+}
+
+// tocExtension adds a table of contents built from the headings that
+// parser.WithAutoHeadingID has assigned IDs to.
+type tocExtension struct {
+	options TOCOptions
+}
+
+// Extend implements goldmark.Extender.
+func (e *tocExtension) Extend(m goldmark.Markdown) {
+	const transformerPriority, rendererPriority = 1000, 500
+	m.Parser().AddOptions(parser.WithASTTransformers(
+		util.Prioritized(&tocTransformer{options: e.options}, transformerPriority),
+	))
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(&tocRenderer{}, rendererPriority),
+	))
+}
+
+// tocTransformer inserts the table of contents at the first placeholder,
+// or at the top of the document when there is none.
+type tocTransformer struct {
+	options TOCOptions
+}
+
+// Transform implements parser.ASTTransformer.
+func (t *tocTransformer) Transform(doc *ast.Document, reader text.Reader, _ parser.Context) {
+	source := reader.Source()
+	lo, hi := t.options.depthRange()
+
+	var headings []*ast.Heading
+	var placeholder ast.Node
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch node := n.(type) {
+		case *ast.Heading:
+			if node.Level >= lo && node.Level <= hi {
+				headings = append(headings, node)
+			}
+			return ast.WalkSkipChildren, nil
+		case *ast.Paragraph, *ast.HTMLBlock:
+			if placeholder == nil && isPlaceholder(node, source, tocPlaceholders) {
+				placeholder = node
+			}
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+
+	var toc ast.Node
+	if len(headings) > 0 {
+		toc = &tocNode{}
+		if t.options.Flat {
+			for _, entry := range buildTOCEntries(headings, source) {
+				toc.AppendChild(toc, entry)
+			}
+		} else {
+			toc.AppendChild(toc, buildTOCList(headings, source))
+		}
+	}
+
+	switch {
+	case placeholder != nil && toc != nil:
+		placeholder.Parent().ReplaceChild(placeholder.Parent(), placeholder, toc)
+	case placeholder != nil:
+		placeholder.Parent().RemoveChild(placeholder.Parent(), placeholder)
+	case toc != nil:
+		doc.InsertBefore(doc, doc.FirstChild(), toc)
+	}
+}
+
+// isPlaceholder reports whether a paragraph or HTML block consists solely
+// of one of placeholders, such as a table of contents marker.
+func isPlaceholder(n ast.Node, source []byte, placeholders []string) bool {
+	var raw bytes.Buffer
+	lines := n.Lines()
+	for i := range lines.Len() {
+		seg := lines.At(i)
+		raw.Write(seg.Value(source))
+	}
+	if b, ok := n.(*ast.HTMLBlock); ok && b.HasClosure() {
+		raw.Write(b.ClosureLine.Value(source))
+	}
+	s := strings.ToLower(strings.TrimSpace(raw.String()))
+	for _, p := range placeholders {
+		if s == p {
+			return true
+		}
+	}
+	return false
+}
+
+// tocDepths returns the nesting depth (1-based) of each heading in the table
+// of contents. A heading deeper than its predecessor nests one level below
+// it; a shallower one returns to the depth of the nearest preceding heading
+// of the same or a shallower level, or to the top.
+func tocDepths(headings []*ast.Heading) []int {
+	depths := make([]int, len(headings))
+	var levels []int
+	for i, h := range headings {
+		for len(levels) > 0 && h.Level <= levels[len(levels)-1] {
+			levels = levels[:len(levels)-1]
+		}
+		levels = append(levels, h.Level)
+		depths[i] = len(levels)
+	}
+	return depths
+}
+
+// buildTOCList nests headings into tight bullet lists following tocDepths.
+func buildTOCList(headings []*ast.Heading, source []byte) *ast.List {
+	stack := []*ast.List{newTOCList()}
+	for i, depth := range tocDepths(headings) {
+		stack = stack[:min(depth, len(stack))]
+		if depth > len(stack) {
+			parent := stack[len(stack)-1].LastChild()
+			sub := newTOCList()
+			parent.AppendChild(parent, sub)
+			stack = append(stack, sub)
+		}
+		top := stack[len(stack)-1]
+		top.AppendChild(top, newTOCItem(headings[i], source))
+	}
+	return stack[0]
+}
+
+// buildTOCEntries returns one tocEntryNode per heading, for TOCOptions.Flat.
+func buildTOCEntries(headings []*ast.Heading, source []byte) []ast.Node {
+	depths := tocDepths(headings)
+	entries := make([]ast.Node, len(headings))
+	for i, h := range headings {
+		entry := &tocEntryNode{Depth: depths[i]}
+		entry.AppendChild(entry, newTOCLabel(h, source))
+		entries[i] = entry
+	}
+	return entries
+}
+
+// newTOCList returns an empty tight bullet list.
+func newTOCList() *ast.List {
+	l := ast.NewList('-')
+	l.IsTight = true
+	return l
+}
+
+// newTOCItem returns a list item holding the label for heading h.
+func newTOCItem(h *ast.Heading, source []byte) *ast.ListItem {
+	item := ast.NewListItem(2) //nolint:mnd // width of the "- " marker
+	item.AppendChild(item, newTOCLabel(h, source))
+	return item
+}
+
+// newTOCLabel returns a text block linking to heading h. Headings without
+// an ID are listed as plain text.
+func newTOCLabel(h *ast.Heading, source []byte) *ast.TextBlock {
+	block := ast.NewTextBlock()
+	var label ast.Node = block
+	if id, ok := h.AttributeString("id"); ok {
+		if b, isBytes := id.([]byte); isBytes {
+			link := ast.NewLink()
+			link.Destination = append([]byte("#"), b...)
+			block.AppendChild(block, link)
+			label = link
+		}
+	}
+	for _, s := range headingLabel(h, source) {
+		label.AppendChild(label, s)
+	}
+	return block
+}
+
+// headingLabel copies the text of a heading's inline content into String
+// nodes, keeping raw typographer entities intact but dropping markup such
+// as emphasis or nested links.
+func headingLabel(h *ast.Heading, source []byte) []ast.Node {
+	var out []ast.Node
+	_ = ast.Walk(h, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch node := n.(type) {
+		case *ast.Text:
+			s := ast.NewString(node.Segment.Value(source))
+			out = append(out, s)
+			if node.SoftLineBreak() {
+				out = append(out, ast.NewString([]byte(" ")))
+			}
+		case *ast.String:
+			s := ast.NewString(node.Value)
+			s.SetCode(node.IsCode())
+			s.SetRaw(node.IsRaw())
+			out = append(out, s)
+		}
+		return ast.WalkContinue, nil
+	})
+	return out
+}
+
+// tocRenderer renders tocNode as a <nav class="toc"> element.
+type tocRenderer struct{}
+
+// RegisterFuncs implements renderer.NodeRenderer.
+func (r *tocRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindTOC, r.renderTOC)
+	reg.Register(KindTOCEntry, r.renderTOCEntry)
+}
+
+func (r *tocRenderer) renderTOC(w util.BufWriter, _ []byte, _ ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString("<nav class=\"toc\">\n")
+	} else {
+		_, _ = w.WriteString("</nav>\n")
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *tocRenderer) renderTOCEntry(
+	w util.BufWriter, _ []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		_, _ = fmt.Fprintf(w, "<div class=\"toc-entry toc-depth-%d\">", node.(*tocEntryNode).Depth)
+	} else {
+		_, _ = w.WriteString("</div>\n")
+	}
+	return ast.WalkContinue, nil
+}
+
+// Compile-time interface checks.
+var (
+	_ goldmark.Extender     = (*tocExtension)(nil)
+	_ renderer.NodeRenderer = (*tocRenderer)(nil)
+)
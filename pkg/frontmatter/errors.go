@@ -0,0 +1,6 @@
+package frontmatter
+
+import "errors"
+
+// ErrInvalidFrontMatter is returned when a front matter block cannot be decoded.
+var ErrInvalidFrontMatter = errors.New("invalid front matter")
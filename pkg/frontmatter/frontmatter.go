@@ -0,0 +1,195 @@
+// Package frontmatter parses YAML and TOML front matter at the start of a
+// Markdown document.
+//
+// YAML front matter is delimited by "---" lines (the closing line may also be
+// "..."); TOML front matter is delimited by "+++" lines. Both delimiters must
+// appear alone on their line and the opening one must be the first line of
+// the document.
+package frontmatter
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	yamlDelimiter    = "---"
+	yamlEndDelimiter = "..."
+	tomlDelimiter    = "+++"
+)
+
+// utf8BOM is skipped when looking for the opening delimiter.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// Metadata holds decoded front matter. Keys are lowercased so lookups are
+// case-insensitive.
+type Metadata map[string]any
+
+// Split separates front matter from the Markdown body. When content has no
+// front matter, Split returns nil metadata and content unchanged. An opening
+// delimiter without a matching closing delimiter, or a YAML block that is not
+// a mapping, e.g. a thematic break followed by a setext heading, is not
+// treated as front matter.
+func Split(content []byte) (Metadata, []byte, error) {
+	rest := bytes.TrimPrefix(content, utf8BOM)
+
+	first, pos := nextLine(rest, 0)
+	var closers []string
+	switch first {
+	case yamlDelimiter:
+		closers = []string{yamlDelimiter, yamlEndDelimiter}
+	case tomlDelimiter:
+		closers = []string{tomlDelimiter}
+	default:
+		return nil, content, nil
+	}
+
+	start := pos
+	for pos < len(rest) {
+		line, next := nextLine(rest, pos)
+		for _, closer := range closers {
+			if line == closer {
+				meta, err := decode(first, rest[start:pos])
+				if err != nil || meta == nil {
+					return nil, content, err
+				}
+				return meta, rest[next:], nil
+			}
+		}
+		pos = next
+	}
+
+	return nil, content, nil
+}
+
+// decode unmarshals the raw front matter block in the format selected by
+// its opening delimiter. It returns nil metadata when a YAML block is valid
+// but not a mapping, and so is Markdown rather than front matter.
+func decode(delimiter string, raw []byte) (Metadata, error) {
+	var m map[string]any
+	var err error
+	if delimiter == tomlDelimiter {
+		err = toml.Unmarshal(raw, &m)
+	} else {
+		var doc yaml.Node
+		if err = yaml.Unmarshal(raw, &doc); err == nil && len(doc.Content) > 0 {
+			if doc.Content[0].Kind != yaml.MappingNode {
+				return nil, nil
+			}
+			err = doc.Content[0].Decode(&m)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFrontMatter, err)
+	}
+
+	meta := make(Metadata, len(m))
+	for k, v := range m {
+		meta[strings.ToLower(k)] = v
+	}
+	return meta, nil
+}
+
+// nextLine returns the line starting at pos with trailing whitespace
+// removed, and the position just after its line ending.
+func nextLine(content []byte, pos int) (string, int) {
+	end := bytes.IndexByte(content[pos:], '\n')
+	if end < 0 {
+		return strings.TrimRight(string(content[pos:]), " \t\r"), len(content)
+	}
+	return strings.TrimRight(string(content[pos:pos+end]), " \t\r"), pos + end + 1
+}
+
+// String returns the value for key as a string. Scalars are formatted with
+// fmt; dates without a time component are formatted as YYYY-MM-DD. Missing
+// keys and non-scalar values yield "".
+func (m Metadata) String(key string) string {
+	return scalarString(m[strings.ToLower(key)])
+}
+
+// scalarString formats a decoded scalar value for String.
+func scalarString(value any) string {
+	switch v := value.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case time.Time:
+		if v.Hour() == 0 && v.Minute() == 0 && v.Second() == 0 && v.Nanosecond() == 0 {
+			return v.Format(time.DateOnly)
+		}
+		return v.Format(time.RFC3339)
+	case bool, int, int64, uint64, float64:
+		return fmt.Sprint(v)
+	default:
+		return ""
+	}
+}
+
+// Strings returns the value for key as a list of strings. A list value
+// yields its scalar elements; a string value is split on commas.
+func (m Metadata) Strings(key string) []string {
+	var out []string
+	switch v := m[strings.ToLower(key)].(type) {
+	case string:
+		for s := range strings.SplitSeq(v, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				out = append(out, s)
+			}
+		}
+	case []any:
+		for _, item := range v {
+			if s := scalarString(item); s != "" {
+				out = append(out, s)
+			}
+		}
+	}
+	return out
+}
+
+// Title returns the "title" value.
+func (m Metadata) Title() string {
+	return m.String("title")
+}
+
+// Author returns the "author" value. A list of authors is joined with ", ".
+func (m Metadata) Author() string {
+	if s := m.String("author"); s != "" {
+		return s
+	}
+	return strings.Join(m.Strings("author"), ", ")
+}
+
+// Description returns the "description" value.
+func (m Metadata) Description() string {
+	return m.String("description")
+}
+
+// Date returns the "date" value.
+func (m Metadata) Date() string {
+	return m.String("date")
+}
+
+// Status returns the "status" value, e.g. "draft".
+func (m Metadata) Status() string {
+	return m.String("status")
+}
+
+// Keywords returns the "keywords" values followed by any "tags" not already
+// listed.
+func (m Metadata) Keywords() []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, key := range []string{"keywords", "tags"} {
+		for _, s := range m.Strings(key) {
+			if !seen[s] {
+				seen[s] = true
+				out = append(out, s)
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,171 @@
+package frontmatter_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/sgaunet/mdtohtml/pkg/frontmatter"
+)
+
+// TestSplit tests front matter detection and body separation
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantTitle string
+		wantBody  string
+		wantMeta  bool
+	}{
+		{
+			name:      "yaml front matter",
+			input:     "---\ntitle: Hello\n---\n# Body\n",
+			wantTitle: "Hello",
+			wantBody:  "# Body\n",
+			wantMeta:  true,
+		},
+		{
+			name:      "yaml closed with dots",
+			input:     "---\ntitle: Dots\n...\nBody",
+			wantTitle: "Dots",
+			wantBody:  "Body",
+			wantMeta:  true,
+		},
+		{
+			name:      "toml front matter",
+			input:     "+++\ntitle = \"Toml\"\n+++\nBody",
+			wantTitle: "Toml",
+			wantBody:  "Body",
+			wantMeta:  true,
+		},
+		{
+			name:      "crlf line endings",
+			input:     "---\r\ntitle: CRLF\r\n---\r\nBody",
+			wantTitle: "CRLF",
+			wantBody:  "Body",
+			wantMeta:  true,
+		},
+		{
+			name:      "byte order mark",
+			input:     "\xEF\xBB\xBF---\ntitle: BOM\n---\nBody",
+			wantTitle: "BOM",
+			wantBody:  "Body",
+			wantMeta:  true,
+		},
+		{
+			name:     "empty front matter",
+			input:    "---\n---\nBody",
+			wantBody: "Body",
+			wantMeta: true,
+		},
+		{
+			name:     "no front matter",
+			input:    "# Title\n\n---\n",
+			wantBody: "# Title\n\n---\n",
+		},
+		{
+			name:     "unterminated front matter",
+			input:    "---\ntitle: Open\n\nBody",
+			wantBody: "---\ntitle: Open\n\nBody",
+		},
+		{
+			name:     "thematic break and setext heading",
+			input:    "---\njust a sentence\n---\nBody",
+			wantBody: "---\njust a sentence\n---\nBody",
+		},
+		{
+			name:     "yaml list",
+			input:    "---\n- one\n- two\n---\nBody",
+			wantBody: "---\n- one\n- two\n---\nBody",
+		},
+		{
+			name:     "thematic break not on first line",
+			input:    "\n---\ntitle: x\n---\n",
+			wantBody: "\n---\ntitle: x\n---\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			meta, body, err := frontmatter.Split([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("Split() error = %v", err)
+			}
+			if (meta != nil) != tt.wantMeta {
+				t.Errorf("Split() metadata = %v, want present=%v", meta, tt.wantMeta)
+			}
+			if got := meta.Title(); got != tt.wantTitle {
+				t.Errorf("Title() = %q, want %q", got, tt.wantTitle)
+			}
+			if string(body) != tt.wantBody {
+				t.Errorf("Split() body = %q, want %q", body, tt.wantBody)
+			}
+		})
+	}
+}
+
+// TestSplit_Invalid tests that malformed front matter is reported
+func TestSplit_Invalid(t *testing.T) {
+	inputs := []string{
+		"---\ntitle: [unclosed\n---\nBody",
+		"+++\ntitle = \n+++\nBody",
+	}
+
+	for _, input := range inputs {
+		_, body, err := frontmatter.Split([]byte(input))
+		if !errors.Is(err, frontmatter.ErrInvalidFrontMatter) {
+			t.Errorf("Split(%q) error = %v, want ErrInvalidFrontMatter", input, err)
+		}
+		if string(body) != input {
+			t.Errorf("Split(%q) body = %q, want input unchanged", input, body)
+		}
+	}
+}
+
+// TestMetadata_Accessors tests typed access to common front matter fields
+func TestMetadata_Accessors(t *testing.T) {
+	input := `---
+Title: Guide
+author:
+  - Ada
+  - Grace
+description: "A <short> guide"
+date: 2024-03-01
+keywords: go, markdown
+tags: [markdown, pdf]
+draft: true
+status: draft
+---
+`
+	meta, _, err := frontmatter.Split([]byte(input))
+	if err != nil {
+		t.Fatalf("Split() error = %v", err)
+	}
+
+	if got := meta.Title(); got != "Guide" {
+		t.Errorf("Title() = %q, want case-insensitive key lookup", got)
+	}
+	if got := meta.Author(); got != "Ada, Grace" {
+		t.Errorf("Author() = %q", got)
+	}
+	if got := meta.Description(); got != "A <short> guide" {
+		t.Errorf("Description() = %q", got)
+	}
+	if got := meta.Date(); got != "2024-03-01" {
+		t.Errorf("Date() = %q", got)
+	}
+	if got := meta.Status(); got != "draft" {
+		t.Errorf("Status() = %q", got)
+	}
+	if got := meta.String("draft"); got != "true" {
+		t.Errorf("String(draft) = %q", got)
+	}
+	if got, want := meta.Keywords(), []string{"go", "markdown", "pdf"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Keywords() = %v, want %v", got, want)
+	}
+
+	var empty frontmatter.Metadata
+	if empty.Title() != "" || empty.Keywords() != nil {
+		t.Error("nil Metadata should return zero values")
+	}
+}
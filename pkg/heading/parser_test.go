@@ -93,6 +93,21 @@ func TestMarkdownTitleExtractor_ExtractTitle(t *testing.T) {
 			input:    "Underlined Title\r\n================\r\n\nContent.",
 			expected: "Underlined Title",
 		},
+		{
+			name:     "front matter title wins over heading",
+			input:    "---\ntitle: Front Matter Title\n---\n# Heading Title\n",
+			expected: "Front Matter Title",
+		},
+		{
+			name:     "front matter without title falls back to heading",
+			input:    "---\nauthor: Someone\n---\n\n# Heading Title\n",
+			expected: "Heading Title",
+		},
+		{
+			name:     "toml front matter title",
+			input:    "+++\ntitle = \"Toml Title\"\n+++\nContent.",
+			expected: "Toml Title",
+		},
 	}
 
 	extractor := heading.NewMarkdownTitleExtractor()
@@ -1,6 +1,10 @@
 package heading
 
-import "strings"
+import (
+	"strings"
+
+	"github.com/sgaunet/mdtohtml/pkg/frontmatter"
+)
 
 // MarkdownTitleExtractor implements TitleExtractor for markdown content.
 type MarkdownTitleExtractor struct{}
@@ -11,8 +15,17 @@ func NewMarkdownTitleExtractor() *MarkdownTitleExtractor {
 }
 
 // ExtractTitle extracts the title from markdown content.
-// It looks for either a # prefix header or an underlined header (===).
+// A "title" in the YAML or TOML front matter takes precedence; otherwise it
+// looks for either a # prefix header or an underlined header (===) at the
+// start of the body.
 func (e *MarkdownTitleExtractor) ExtractTitle(content []byte) string {
+	if meta, body, err := frontmatter.Split(content); err == nil {
+		if title := meta.Title(); title != "" {
+			return title
+		}
+		content = body
+	}
+
 	pos := skipBlankLines(content)
 	if pos >= len(content) {
 		return ""
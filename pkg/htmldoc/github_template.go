@@ -3,6 +3,7 @@ package htmldoc
 import (
 	_ "embed"
 	"fmt"
+	"html"
 	"strings"
 )
 
@@ -38,16 +39,40 @@ func NewGitHubTemplateWithAdditionalCSS(additional string) *GitHubTemplate {
 
 // Wrap wraps HTML content with a complete HTML document structure.
 func (t *GitHubTemplate) Wrap(content, title string) string {
+	return t.WrapWithInfo(content, DocumentInfo{Title: title})
+}
+
+// WrapWithInfo wraps HTML content with a complete HTML document structure,
+// adding author, description, date, keywords and status <meta> tags when set.
+// The title and metadata are plain text and escaped; content is HTML.
+func (t *GitHubTemplate) WrapWithInfo(content string, info DocumentInfo) string {
 	return fmt.Sprintf(`<!DOCTYPE html>
 <html>
 <head>
 <meta charset="UTF-8">
-<title>%s</title>
+%s<title>%s</title>
 </head>
 <body>
 %s
 </body>
-</html>`, title, content)
+</html>`, metaTags(info), html.EscapeString(info.Title), content)
+}
+
+// metaTags renders the <meta> elements for the non-empty fields of info.
+func metaTags(info DocumentInfo) string {
+	var b strings.Builder
+	for _, m := range []struct{ name, content string }{
+		{"author", info.Author},
+		{"description", info.Description},
+		{"date", info.Date},
+		{"keywords", strings.Join(info.Keywords, ", ")},
+		{"status", info.Status},
+	} {
+		if m.content != "" {
+			fmt.Fprintf(&b, "<meta name=\"%s\" content=\"%s\">\n", m.name, html.EscapeString(m.content))
+		}
+	}
+	return b.String()
 }
 
 // InjectCSS injects CSS into an HTML document.
@@ -8,4 +8,24 @@ type HTMLTemplate interface {
 
 	// InjectCSS injects CSS into an HTML document
 	InjectCSS(html, css string) string
-}
\ No newline at end of file
+}
+
+// DocumentInfo holds document-level metadata emitted in the HTML <head>.
+type DocumentInfo struct {
+	Title       string
+	Author      string
+	Description string
+	Date        string
+	Keywords    []string
+	Status      string
+}
+
+// InfoTemplate is implemented by templates that can emit <meta> tags for
+// document metadata in addition to the title.
+type InfoTemplate interface {
+	HTMLTemplate
+
+	// WrapWithInfo wraps HTML content with a complete HTML document structure
+	// whose <head> carries the given metadata
+	WrapWithInfo(content string, info DocumentInfo) string
+}
@@ -47,7 +47,7 @@ func TestGitHubTemplate_Wrap(t *testing.T) {
 			content: "<p>Content</p>",
 			title:   "Title with <special> & \"characters\"",
 			contains: []string{
-				"<title>Title with <special> & \"characters\"</title>",
+				"<title>Title with &lt;special&gt; &amp; &#34;characters&#34;</title>",
 			},
 		},
 		{
@@ -84,6 +84,47 @@ func TestGitHubTemplate_Wrap(t *testing.T) {
 	}
 }
 
+// TestGitHubTemplate_WrapWithInfo tests that document metadata is emitted as <meta> tags
+func TestGitHubTemplate_WrapWithInfo(t *testing.T) {
+	var tmpl htmldoc.InfoTemplate = htmldoc.NewGitHubTemplate()
+
+	result := tmpl.WrapWithInfo("<p>body</p>", htmldoc.DocumentInfo{
+		Title:       "Doc",
+		Author:      "Ada & Grace",
+		Description: `A "quoted" summary`,
+		Keywords:    []string{"go", "markdown"},
+		Status:      "draft",
+	})
+
+	for _, expected := range []string{
+		"<title>Doc</title>",
+		`<meta name="author" content="Ada &amp; Grace">`,
+		`<meta name="description" content="A &#34;quoted&#34; summary">`,
+		`<meta name="keywords" content="go, markdown">`,
+		`<meta name="status" content="draft">`,
+		"<p>body</p>",
+	} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("WrapWithInfo() result does not contain %q\nGot: %s", expected, result)
+		}
+	}
+	if strings.Contains(result, `name="date"`) {
+		t.Errorf("WrapWithInfo() should omit empty fields\nGot: %s", result)
+	}
+
+	// The title is text, so markup in it must not end the <title> element
+	escaped := tmpl.WrapWithInfo("<p>body</p>", htmldoc.DocumentInfo{Title: "a </title><script>alert(1)</script>"})
+	if !strings.Contains(escaped, "<title>a &lt;/title&gt;&lt;script&gt;alert(1)&lt;/script&gt;</title>") ||
+		strings.Contains(escaped, "<script>") {
+		t.Errorf("WrapWithInfo() should escape the title\nGot: %s", escaped)
+	}
+
+	// Wrap without metadata emits no <meta name> tags
+	if plain := tmpl.Wrap("<p>body</p>", "Doc"); strings.Contains(plain, "<meta name=") {
+		t.Errorf("Wrap() should not emit metadata tags\nGot: %s", plain)
+	}
+}
+
 // TestGitHubTemplate_InjectCSS tests CSS injection functionality
 func TestGitHubTemplate_InjectCSS(t *testing.T) {
 	tests := []struct {
@@ -180,7 +180,7 @@ func TestValidationWorkflow(t *testing.T) {
 		conv := converter.NewGoldmarkConverter(converter.DefaultOptions())
 		val := validator.NewGoldmarkValidator(conv)
 
-		err := val.ValidateFile(filePath)
+		_, err := val.ValidateFile(filePath)
 		
 		if tc.valid && err != nil {
 			t.Errorf("File %s should be valid but got error: %v", tc.filename, err)
@@ -0,0 +1,139 @@
+package pdf
+
+import (
+	"strings"
+
+	folio "github.com/carlos7ags/folio/document"
+	folioHTML "github.com/carlos7ags/folio/html"
+	"github.com/carlos7ags/folio/layout"
+	"golang.org/x/net/html"
+)
+
+// headingAnchor is an h1-h6 element with an id attribute.
+type headingAnchor struct {
+	id   string
+	text string
+}
+
+// pageHeading is a heading with the page it is laid out on.
+type pageHeading struct {
+	layout.HeadingInfo
+	page int // 0-based page index
+}
+
+// addHeadingDests registers a named destination for every heading id in
+// htmlStr so that internal links such as the generated table of contents
+// (<a href="#id">) resolve. folio does not map HTML ids to destinations
+// itself, so headings are the laid out headings of the HTML (see
+// layoutHeadings), which are matched to their ids in document order by
+// text. pageOffset is the number of pages before the body.
+//
+// Nothing is registered unless the HTML contains an internal link (see
+// hasInternalLinks), so headings need not be laid out otherwise.
+func addHeadingDests(doc *folio.Document, htmlStr string, headings []pageHeading, pageOffset int) {
+	if !hasInternalLinks(htmlStr) {
+		return
+	}
+	addNamedDests(doc, headingAnchors(htmlStr), headings, pageOffset)
+}
+
+// hasInternalLinks reports whether htmlStr links to a fragment of itself.
+func hasInternalLinks(htmlStr string) bool {
+	return strings.Contains(htmlStr, `href="#`)
+}
+
+// layoutHeadings lays out the elements of result once more with the page
+// geometry of the document, to learn where each heading lands, and returns
+// the headings in document order and the number of pages.
+func layoutHeadings(result *folioHTML.ConvertResult, pageSize folio.PageSize,
+	margins layout.Margins) ([]pageHeading, int) {
+	r := layout.NewRenderer(pageSize.Width, pageSize.Height, margins)
+	if result.MarginBoxes != nil {
+		r.SetMarginBoxes(result.MarginBoxes)
+	}
+	if result.FirstMarginBoxes != nil {
+		r.SetFirstMarginBoxes(result.FirstMarginBoxes)
+	}
+	for _, e := range result.Elements {
+		r.Add(e)
+	}
+
+	pages := r.Render()
+	var headings []pageHeading
+	for pageIdx, page := range pages {
+		for _, h := range page.Headings {
+			headings = append(headings, pageHeading{HeadingInfo: h, page: pageIdx})
+		}
+	}
+	return headings, len(pages)
+}
+
+// addNamedDests registers a destination named after each anchor at the
+// laid out heading with the same text, matching both in document order.
+// pageOffset is added to the page indexes of headings.
+func addNamedDests(doc *folio.Document, anchors []headingAnchor, headings []pageHeading, pageOffset int) {
+	next := 0
+	for _, h := range headings {
+		for i := next; i < len(anchors); i++ {
+			if anchors[i].text == collapseSpace(h.Text) {
+				doc.AddNamedDest(folio.NamedDest{
+					Name:      anchors[i].id,
+					PageIndex: pageOffset + h.page,
+					FitType:   "XYZ",
+					Top:       h.Y,
+				})
+				next = i + 1
+				break
+			}
+		}
+	}
+}
+
+// headingAnchors returns the id and collapsed text of every heading in
+// htmlStr that carries an id, in document order.
+func headingAnchors(htmlStr string) []headingAnchor {
+	root, err := html.Parse(strings.NewReader(htmlStr))
+	if err != nil {
+		return nil
+	}
+	var anchors []headingAnchor
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && isHeadingTag(n.Data) {
+			for _, a := range n.Attr {
+				if a.Key == "id" && a.Val != "" {
+					anchors = append(anchors, headingAnchor{id: a.Val, text: collapseSpace(nodeText(n))})
+					break
+				}
+			}
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(root)
+	return anchors
+}
+
+// isHeadingTag reports whether tag is h1 through h6.
+func isHeadingTag(tag string) bool {
+	return len(tag) == 2 && tag[0] == 'h' && tag[1] >= '1' && tag[1] <= '6'
+}
+
+// nodeText concatenates the text nodes below n.
+func nodeText(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var sb strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		sb.WriteString(nodeText(c))
+	}
+	return sb.String()
+}
+
+// collapseSpace trims s and replaces internal whitespace runs with one space.
+func collapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,281 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	folio "github.com/carlos7ags/folio/document"
+	folioHTML "github.com/carlos7ags/folio/html"
+	"github.com/carlos7ags/folio/layout"
+	"golang.org/x/net/html"
+)
+
+// Chapter is a Markdown file of a book and the chapters nested below it,
+// e.g. the sections of a part.
+type Chapter struct {
+	// Title is the bookmark title of the chapter. Empty uses the document
+	// title of the file: its front matter title or first heading.
+	Title string
+
+	// Path is the Markdown file.
+	Path string
+
+	// Children are the chapters nested below this one.
+	Children []Chapter
+}
+
+// bookChapter is a chapter being rendered into a book.
+type bookChapter struct {
+	Chapter
+	id           string // prefix of the heading ids of the chapter
+	root         *html.Node
+	firstHeading string // prefixed id of the first heading, or empty
+	htmlStr      string
+	result       *folioHTML.ConvertResult
+	headings     []pageHeading
+	start, pages int
+	children     []*bookChapter
+}
+
+// ConvertFiles renders the Markdown files at inputPaths, in order, into a
+// single PDF at outputPath, one chapter per file. See ConvertBook.
+func (c *Converter) ConvertFiles(inputPaths []string, outputPath string) error {
+	chapters := make([]Chapter, len(inputPaths))
+	for i, p := range inputPaths {
+		chapters[i] = Chapter{Path: p}
+	}
+	return c.ConvertBook(chapters, outputPath)
+}
+
+// ConvertBook renders chapters, depth first, into a single PDF at
+// outputPath. Each chapter starts on a new page and its relative images
+// resolve against its own directory. Heading ids are prefixed per chapter so
+// they stay unique, and links to the Markdown file of another chapter point
+// at its first heading, or at the heading named by the fragment. Every
+// chapter gets a bookmark holding the bookmarks of its headings, nested by
+// level, followed by those of its child chapters.
+//
+// The page layout, @page rules and document information come from the
+// first chapter. The cover and contents pages, when configured, come
+// before the first chapter and the contents page lists the headings of all
+// chapters.
+func (c *Converter) ConvertBook(chapters []Chapter, outputPath string) error {
+	var all []*bookChapter
+	var flatten func(chapters []Chapter) []*bookChapter
+	flatten = func(chapters []Chapter) []*bookChapter {
+		var nodes []*bookChapter
+		for _, ch := range chapters {
+			bc := &bookChapter{Chapter: ch, id: "chapter-" + strconv.Itoa(len(all)+1)}
+			all = append(all, bc)
+			bc.children = flatten(ch.Children)
+			nodes = append(nodes, bc)
+		}
+		return nodes
+	}
+	tree := flatten(chapters)
+	if len(all) == 0 {
+		return ErrEmptyBook
+	}
+
+	byPath := make(map[string]*bookChapter, len(all))
+	for _, ch := range all {
+		if err := c.parseChapter(ch); err != nil {
+			return err
+		}
+		if key, err := filepath.Abs(ch.Path); err == nil {
+			if _, dup := byPath[key]; !dup {
+				byPath[key] = ch
+			}
+		}
+	}
+	fonts := c.newFontSet()
+	for _, ch := range all {
+		if err := ch.render(byPath, fonts); err != nil {
+			return err
+		}
+	}
+
+	doc, margins := c.newDocument(all[0].result)
+	c.setInfo(doc, all[0].result.Metadata)
+	var entries []contentsEntry
+	pages := 0
+	for _, ch := range all {
+		ch.headings, ch.pages = layoutHeadings(ch.result, c.pageSize, margins)
+		ch.start = pages
+		if len(ch.result.Elements) > 0 {
+			entries = append(entries, contentsEntries(ch.headings, c.contentsDepth(), ch.start)...)
+			pages += ch.pages
+		}
+	}
+	pf := fonts.pageFonts()
+	front := c.addFrontPages(doc, entries, pf, margins)
+	for _, ch := range all {
+		if len(ch.result.Elements) == 0 {
+			continue
+		}
+		if ch.start > 0 {
+			doc.Add(layout.NewAreaBreak())
+		}
+		for _, e := range ch.result.Elements {
+			doc.Add(e)
+		}
+		addNamedDests(doc, headingAnchors(ch.htmlStr), ch.headings, front+ch.start)
+	}
+	for _, ch := range all {
+		// Empty chapters at the end point at the last page.
+		ch.start = front + min(ch.start, max(pages-1, 0))
+	}
+	for _, ch := range tree {
+		out := ch.outline()
+		o := doc.AddOutline(out.Title, out.Dest)
+		o.Children = out.Children
+	}
+
+	c.decoratePages(doc, metaContent(all[0].root, "status"), pf, margins,
+		newRunningValues(all[0].result.Metadata.Title, all[0].Path, doc.Info.CreationDate))
+	return saveDocument(doc, outputPath)
+}
+
+// parseChapter converts the Markdown file of ch to HTML and prefixes the
+// ids in it.
+func (c *Converter) parseChapter(ch *bookChapter) error {
+	input, err := readInput(ch.Path)
+	if err != nil {
+		return err
+	}
+	htmlBytes, err := c.htmlConv.Convert(input)
+	if err != nil {
+		return fmt.Errorf("markdown to HTML of '%s': %w", ch.Path, err)
+	}
+	ch.root, err = html.Parse(bytes.NewReader(htmlBytes))
+	if err != nil {
+		return fmt.Errorf("parsing HTML of '%s': %w", ch.Path, err)
+	}
+	walkElements(ch.root, func(n *html.Node) {
+		for i, a := range n.Attr {
+			if a.Key == "id" && a.Val != "" {
+				n.Attr[i].Val = ch.id + "-" + a.Val
+				if ch.firstHeading == "" && isHeadingTag(n.Data) {
+					ch.firstHeading = n.Attr[i].Val
+				}
+			}
+		}
+	})
+	return nil
+}
+
+// render rewrites the links of ch to its own headings and to the chapters
+// in byPath, keyed by absolute path, flattens code blocks, applies fonts and
+// lays out the resulting HTML.
+func (ch *bookChapter) render(byPath map[string]*bookChapter, fonts *fontSet) error {
+	dir := filepath.Dir(ch.Path)
+	walkElements(ch.root, func(n *html.Node) {
+		if n.Data != "a" {
+			return
+		}
+		for i, a := range n.Attr {
+			if a.Key == "href" {
+				n.Attr[i].Val = ch.rewriteLink(a.Val, dir, byPath)
+			}
+		}
+	})
+	flattenCode(ch.root)
+	fonts.apply(ch.root)
+	var buf bytes.Buffer
+	if err := html.Render(&buf, ch.root); err != nil {
+		return fmt.Errorf("rendering HTML of '%s': %w", ch.Path, err)
+	}
+	ch.htmlStr = buf.String()
+	result, err := folioHTML.ConvertFull(ch.htmlStr, &folioHTML.Options{BasePath: dir})
+	if err != nil {
+		return fmt.Errorf("HTML to PDF of '%s': %w", ch.Path, err)
+	}
+	ch.result = result
+	return nil
+}
+
+// rewriteLink returns the destination within the book of the link href of
+// ch, whose file is in dir. Fragment-only links get the id prefix of ch and
+// relative links to the file of a chapter point at the heading of that
+// chapter. Other links are returned unchanged.
+func (ch *bookChapter) rewriteLink(href, dir string, byPath map[string]*bookChapter) string {
+	p, fragment, _ := strings.Cut(href, "#")
+	if p == "" {
+		if fragment == "" {
+			return href
+		}
+		return "#" + ch.id + "-" + fragment
+	}
+	u, err := url.Parse(p)
+	if err != nil || u.Scheme != "" || u.Host != "" || u.Path == "" || strings.HasPrefix(u.Path, "/") {
+		return href
+	}
+	target, err := filepath.Abs(filepath.Join(dir, filepath.FromSlash(u.Path)))
+	if err != nil {
+		return href
+	}
+	to, ok := byPath[target]
+	switch {
+	case !ok:
+		return href
+	case fragment != "":
+		return "#" + to.id + "-" + fragment
+	case to.firstHeading != "":
+		return "#" + to.firstHeading
+	default:
+		return href
+	}
+}
+
+// title returns the bookmark title of ch.
+func (ch *bookChapter) title() string {
+	if ch.Title != "" {
+		return ch.Title
+	}
+	if t := collapseSpace(ch.result.Metadata.Title); t != "" {
+		return t
+	}
+	return strings.TrimSuffix(filepath.Base(ch.Path), filepath.Ext(ch.Path))
+}
+
+// outline returns the bookmark of ch. When the chapter opens with a heading
+// carrying its title, the bookmark points at that heading and takes over
+// its nested headings.
+func (ch *bookChapter) outline() folio.Outline {
+	o := folio.Outline{Title: ch.title(), Dest: folio.FitDest(ch.start)}
+	headings := nestHeadings(ch.headings, ch.start)
+	if len(headings) > 0 && headings[0].Title == o.Title {
+		o.Dest = headings[0].Dest
+		o.Children = append(headings[0].Children, headings[1:]...)
+	} else {
+		o.Children = headings
+	}
+	for _, child := range ch.children {
+		o.Children = append(o.Children, child.outline())
+	}
+	return o
+}
+
+// nestHeadings returns the bookmarks of headings, each holding the deeper
+// headings that follow it. pageOffset is added to the page indexes.
+func nestHeadings(headings []pageHeading, pageOffset int) []folio.Outline {
+	var outlines []folio.Outline
+	for i := 0; i < len(headings); {
+		h := headings[i]
+		next := i + 1
+		for next < len(headings) && headings[next].Level > h.Level {
+			next++
+		}
+		outlines = append(outlines, folio.Outline{
+			Title:    collapseSpace(h.Text),
+			Dest:     folio.XYZDest(pageOffset+h.page, 0, h.Y, 0),
+			Children: nestHeadings(headings[i+1:next], pageOffset),
+		})
+		i = next
+	}
+	return outlines
+}
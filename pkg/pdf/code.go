@@ -0,0 +1,240 @@
+package pdf
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// preClass is the class of the blocks replacing code blocks; see flattenPre.
+const preClass = "mdtohtml-pre"
+
+// Geometry of the blocks replacing code blocks, in em of the code font.
+const (
+	monoAdvance   = 0.6  // advance of Courier and most monospaced fonts
+	preLineHeight = 1.45 // as in preCSS
+	tabWidth      = 4    // columns, as folio draws tabs in pre elements
+)
+
+// preCSS styles the blocks replacing code blocks like the GitHub stylesheet
+// styles pre elements.
+const preCSS = `body .` + preClass + ` {
+  white-space: pre;
+  font-family: monospace;
+  padding: 16px;
+  margin-bottom: 16px;
+  font-size: 85%;
+  line-height: 1.45;
+  background-color: #f6f8fa;
+}
+`
+
+// tokenProperties are the CSS properties of highlighted tokens kept in the
+// blocks replacing code blocks.
+var tokenProperties = []string{"color", "font-weight", "font-style"}
+
+// codeToken is a piece of the text of a code block with the style of its
+// highlighted token, e.g. "color:#cf222e", or "" for plain text.
+type codeToken struct {
+	text       string
+	style      string
+	lineNumber bool
+}
+
+// flattenCode adds preCSS to the head of the document root and replaces
+// its pre elements (see flattenPre). The stylesheet is added even without
+// the default CSS, as folio styles pre elements itself.
+func flattenCode(root *html.Node) {
+	var head *html.Node
+	var pres []*html.Node
+	walkElements(root, func(n *html.Node) {
+		switch {
+		case head == nil && n.DataAtom == atom.Head:
+			head = n
+		case n.DataAtom == atom.Pre && !slices.ContainsFunc(pres, func(p *html.Node) bool { return isAncestor(p, n) }):
+			pres = append(pres, n)
+		}
+	})
+	if len(pres) == 0 {
+		return
+	}
+	if head != nil {
+		style := &html.Node{Type: html.ElementNode, Data: "style", DataAtom: atom.Style}
+		style.AppendChild(&html.Node{Type: html.TextNode, Data: preCSS})
+		head.AppendChild(style)
+	}
+	for _, n := range pres {
+		flattenPre(n)
+	}
+}
+
+// isAncestor reports whether a is an ancestor of n.
+func isAncestor(a, n *html.Node) bool {
+	for p := n.Parent; p != nil; p = p.Parent {
+		if p == a {
+			return true
+		}
+	}
+	return false
+}
+
+// flattenPre replaces the pre element n with a div of class preClass
+// holding a div per line of its code. folio draws pre elements with the
+// standard fonts whatever their font-family and without the colours of
+// highlighted code, and collapses the spaces of all text, so the tokens
+// keep their colours in spans, the spaces between them start the next
+// span, and the indentation of a line becomes its left padding, or the
+// width of its line number.
+func flattenPre(n *html.Node) {
+	div := &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div}
+	for _, a := range n.Attr {
+		if a.Key == "class" {
+			a.Val = strings.TrimSpace(a.Val + " " + preClass)
+		}
+		div.Attr = append(div.Attr, a)
+	}
+	if attrValue(div, "class") == "" {
+		div.Attr = append(div.Attr, html.Attribute{Key: "class", Val: preClass})
+	}
+	for _, tokens := range codeLines(n) {
+		div.AppendChild(codeLine(tokens))
+	}
+	n.Parent.InsertBefore(div, n)
+	n.Parent.RemoveChild(n)
+}
+
+// codeLines returns the tokens of each line of the code block n, without
+// the blank lines at its start and end.
+func codeLines(n *html.Node) [][]codeToken {
+	lines := [][]codeToken{nil}
+	var collect func(n *html.Node, style string, lineNumber bool)
+	collect = func(n *html.Node, style string, lineNumber bool) {
+		if n.Type == html.TextNode {
+			for i, text := range strings.Split(n.Data, "\n") {
+				if i > 0 {
+					lines = append(lines, nil)
+				}
+				if text != "" {
+					lines[len(lines)-1] = append(lines[len(lines)-1], codeToken{text, style, lineNumber})
+				}
+			}
+			return
+		}
+		if n.Type == html.ElementNode {
+			css := attrValue(n, "style")
+			if s := tokenStyle(css); s != "" {
+				style = s
+			}
+			// Chroma keeps line numbers out of the selection.
+			lineNumber = lineNumber || strings.Contains(css, "user-select:none")
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			collect(c, style, lineNumber)
+		}
+	}
+	collect(n, "", false)
+
+	blank := func(tokens []codeToken) bool {
+		return !slices.ContainsFunc(tokens, func(t codeToken) bool {
+			return t.lineNumber || strings.TrimSpace(t.text) != ""
+		})
+	}
+	for len(lines) > 0 && blank(lines[0]) {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && blank(lines[len(lines)-1]) {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// tokenStyle returns the declarations of the inline style css that are
+// kept for highlighted tokens.
+func tokenStyle(css string) string {
+	var kept []string
+	for decl := range strings.SplitSeq(css, ";") {
+		prop, _, _ := strings.Cut(decl, ":")
+		if slices.Contains(tokenProperties, strings.TrimSpace(prop)) {
+			kept = append(kept, strings.TrimSpace(decl))
+		}
+	}
+	return strings.Join(kept, ";")
+}
+
+// codeLine returns the div drawing the tokens of a line of code.
+func codeLine(tokens []codeToken) *html.Node {
+	line := &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div}
+	var number, numberStyle, pending string
+	indent := 0
+	for _, t := range tokens {
+		if t.lineNumber {
+			number, numberStyle = number+t.text, t.style
+			continue
+		}
+		text := pending + strings.ReplaceAll(t.text, "\t", strings.Repeat(" ", tabWidth))
+		code := strings.TrimRightFunc(text, unicode.IsSpace)
+		pending = text[len(code):]
+		if code == "" {
+			continue
+		}
+		if line.FirstChild == nil {
+			trimmed := strings.TrimLeftFunc(code, unicode.IsSpace)
+			indent = utf8.RuneCountInString(code) - utf8.RuneCountInString(trimmed)
+			code = trimmed
+		}
+		line.AppendChild(codeSpan(code, t.style))
+	}
+
+	switch {
+	case number != "":
+		// The number is a box as wide as itself and the indentation, and
+		// is followed by a space.
+		width := float64(utf8.RuneCountInString(number)+indent) * monoAdvance
+		span := codeSpan(strings.TrimSpace(number), fmt.Sprintf("display:inline-block;width:%.4gem;%s", width, numberStyle))
+		line.InsertBefore(span, line.FirstChild)
+	case line.FirstChild == nil:
+		line.Attr = []html.Attribute{{Key: "style", Val: fmt.Sprintf("height:%.4gem", preLineHeight)}}
+	case indent > 0:
+		line.Attr = []html.Attribute{{Key: "style", Val: fmt.Sprintf("padding-left:%.4gem", float64(indent)*monoAdvance)}}
+	}
+	return line
+}
+
+// codeSpan returns a span of text with the inline style, or the bare text
+// when style is empty.
+func codeSpan(text, style string) *html.Node {
+	textNode := &html.Node{Type: html.TextNode, Data: text}
+	if style == "" {
+		return textNode
+	}
+	span := &html.Node{Type: html.ElementNode, Data: "span", DataAtom: atom.Span,
+		Attr: []html.Attribute{{Key: "style", Val: style}}}
+	span.AppendChild(textNode)
+	return span
+}
+
+// attrValue returns the value of the attribute key of n, or "" when it has
+// none.
+func attrValue(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// walkElements calls fn for every element below n, in document order.
+func walkElements(n *html.Node, fn func(*html.Node)) {
+	if n.Type == html.ElementNode {
+		fn(n)
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		walkElements(c, fn)
+	}
+}
@@ -0,0 +1,132 @@
+package pdf
+
+import (
+	"strconv"
+	"strings"
+
+	folio "github.com/carlos7ags/folio/document"
+	"github.com/carlos7ags/folio/layout"
+)
+
+// DefaultContentsDepth is the deepest heading level listed on the contents
+// page unless Options.ContentsDepth is set.
+const DefaultContentsDepth = 3
+
+// maxHeadingLevel is the deepest heading level, h6.
+const maxHeadingLevel = 6
+
+// contentsTitle is the heading of the contents page.
+const contentsTitle = "Contents"
+
+// Contents page typography.
+const (
+	contentsTitleSize = 18.0
+	contentsEntrySize = 11.0
+	contentsLeading   = 1.6
+	contentsIndent    = 14.0 // per heading level below the shallowest
+	contentsLeader    = ". "
+	contentsEllipsis  = "…"
+
+	// contentsNumberWidth is the room kept for page numbers after the leaders.
+	contentsNumberWidth = " 000"
+)
+
+// contentsEntry is a line of the contents page.
+type contentsEntry struct {
+	title string
+	level int
+	page  int // 0-based index among the body pages
+}
+
+// contentsEntries returns the contents entries of headings up to level
+// depth, whose pages are offset by pageOffset.
+func contentsEntries(headings []pageHeading, depth, pageOffset int) []contentsEntry {
+	var entries []contentsEntry
+	for _, h := range headings {
+		if title := collapseSpace(h.Text); title != "" && h.Level <= depth {
+			entries = append(entries, contentsEntry{title: title, level: h.Level, page: pageOffset + h.page})
+		}
+	}
+	return entries
+}
+
+// contentsTops returns the baseline of the first entry on the first and
+// on every further contents page.
+func contentsTops(pageSize folio.PageSize, margins layout.Margins) (first, rest float64) {
+	top := pageSize.Height - margins.Top
+	return top - contentsTitleSize*3, top - contentsEntrySize //nolint:mnd // below the title and a blank line
+}
+
+// contentsPageCount returns the number of pages listing entries.
+func contentsPageCount(entries int, pageSize folio.PageSize, margins layout.Margins) int {
+	lines := func(top float64) int {
+		return max(int((top-margins.Bottom)/(contentsEntrySize*contentsLeading))+1, 1)
+	}
+	firstTop, restTop := contentsTops(pageSize, margins)
+	first, rest := lines(firstTop), lines(restTop)
+	if entries <= first {
+		return 1
+	}
+	return 1 + (entries-first+rest-1)/rest
+}
+
+// addContentsPages adds the contents pages listing entries to doc. Each
+// line links to the page of its heading and shows its page number,
+// counting all pages of the document, with the body starting at page
+// index bodyStart. Dot leaders run from the title to a common column
+// before the page numbers.
+func addContentsPages(doc *folio.Document, entries []contentsEntry, bodyStart int, fonts pageFonts,
+	pageSize folio.PageSize, margins layout.Margins) {
+	left := margins.Left
+	right := pageSize.Width - margins.Right
+	firstTop, restTop := contentsTops(pageSize, margins)
+	leaderWidth := fonts.regular.MeasureString(contentsLeader, contentsEntrySize)
+	leadersEnd := right - fonts.regular.MeasureString(contentsNumberWidth, contentsEntrySize)
+
+	minLevel := 0
+	for _, e := range entries {
+		if minLevel == 0 || e.level < minLevel {
+			minLevel = e.level
+		}
+	}
+
+	page := doc.AddPage()
+	fonts.bold.draw(page, contentsTitle, contentsTitleSize, left, pageSize.Height-margins.Top-contentsTitleSize)
+	y := firstTop
+	for _, e := range entries {
+		if y < margins.Bottom {
+			page = doc.AddPage()
+			y = restTop
+		}
+		target := bodyStart + e.page
+		number := strconv.Itoa(target + 1)
+		numberX := right - fonts.regular.MeasureString(number, contentsEntrySize)
+		x := left + float64(e.level-minLevel)*contentsIndent
+		end := min(leadersEnd, numberX)
+		title := truncateText(e.title, fonts.regular, contentsEntrySize, end-x-leaderWidth)
+		titleEnd := x + fonts.regular.MeasureString(title+" ", contentsEntrySize)
+		fonts.regular.draw(page, title, contentsEntrySize, x, y)
+		if n := int((end - titleEnd) / leaderWidth); n > 0 {
+			fonts.regular.draw(page, strings.Repeat(contentsLeader, n), contentsEntrySize, end-float64(n)*leaderWidth, y)
+		}
+		fonts.regular.draw(page, number, contentsEntrySize, numberX, y)
+		page.AddPageLink([4]float64{x, y - contentsEntrySize/4, right, y + contentsEntrySize}, target) //nolint:mnd // below the baseline
+		y -= contentsEntrySize * contentsLeading
+	}
+}
+
+// truncateText shortens text with an ellipsis until it is no wider than
+// width.
+func truncateText(text string, f pageFont, size, width float64) string {
+	if f.MeasureString(text, size) <= width {
+		return text
+	}
+	runes := []rune(text)
+	for n := len(runes) - 1; n > 0; n-- {
+		s := strings.TrimRight(string(runes[:n]), " ") + contentsEllipsis
+		if f.MeasureString(s, size) <= width {
+			return s
+		}
+	}
+	return contentsEllipsis
+}
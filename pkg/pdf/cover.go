@@ -0,0 +1,156 @@
+package pdf
+
+import (
+	"cmp"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	folio "github.com/carlos7ags/folio/document"
+	folioimage "github.com/carlos7ags/folio/image"
+	"github.com/carlos7ags/folio/layout"
+)
+
+// Cover configures the cover page drawn before the document.
+type Cover struct {
+	// Enabled draws the cover page.
+	Enabled bool
+
+	// Title defaults to the document title.
+	Title string
+
+	// Subtitle is drawn below the title. Empty draws none.
+	Subtitle string
+
+	// Author defaults to the PDF author; see Options.Author.
+	Author string
+
+	// Date defaults to the creation date, written like "January 2, 2006".
+	Date string
+
+	// Logo is a PNG or JPEG image drawn above the title. Empty draws none.
+	Logo string
+}
+
+// Cover page typography.
+const (
+	coverTitleSize    = 28.0
+	coverSubtitleSize = 16.0
+	coverByLineSize   = 12.0
+	coverLeading      = 1.3
+	coverDateLayout   = "January 2, 2006"
+)
+
+// Cover page geometry, as fractions of the page or content area.
+const (
+	coverLogoMaxWidth  = 0.4  // of the content width
+	coverLogoMaxHeight = 0.25 // of the page height
+	coverTitleTop      = 0.6  // baseline of the first title line, from the bottom of the page
+	coverSubtitleGray  = 0.35
+)
+
+// loadImage loads the PNG or JPEG image at path, returning errInvalid
+// wrapped when it is neither or cannot be read.
+func loadImage(path string, errInvalid error) (*folioimage.Image, error) {
+	var img *folioimage.Image
+	var err error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".png":
+		img, err = folioimage.LoadPNG(path)
+	case ".jpg", ".jpeg":
+		img, err = folioimage.LoadJPEG(path)
+	default:
+		return nil, fmt.Errorf("%w: %s (use a PNG or JPEG image)", errInvalid, path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", errInvalid, path, err)
+	}
+	return img, nil
+}
+
+// addCoverPage adds the cover page to doc, whose document information must
+// already be set. logo is the loaded Cover.Logo or nil.
+func addCoverPage(doc *folio.Document, cover Cover, logo *folioimage.Image, fonts pageFonts,
+	pageSize folio.PageSize, margins layout.Margins) {
+	page := doc.AddPage()
+	left := margins.Left
+	width := pageSize.Width - margins.Left - margins.Right
+
+	if logo != nil {
+		w := min(width*coverLogoMaxWidth, float64(logo.Width()))
+		h := w / logo.AspectRatio()
+		if maxH := pageSize.Height * coverLogoMaxHeight; h > maxH {
+			h = maxH
+			w = h * logo.AspectRatio()
+		}
+		page.AddImage(logo, left+(width-w)/2, pageSize.Height-margins.Top-h, w, h) //nolint:mnd // centered between the margins
+	}
+
+	y := pageSize.Height * coverTitleTop
+	title := cmp.Or(cover.Title, doc.Info.Title)
+	for _, line := range wrapText(title, fonts.bold, coverTitleSize, width) {
+		drawCentered(page, line, fonts.bold, coverTitleSize, left, width, y)
+		y -= coverTitleSize * coverLeading
+	}
+	if cover.Subtitle != "" {
+		y -= coverSubtitleSize * (coverLeading - 1)
+		for _, line := range wrapText(cover.Subtitle, fonts.regular, coverSubtitleSize, width) {
+			drawGray(page, coverSubtitleGray, func() {
+				drawCentered(page, line, fonts.regular, coverSubtitleSize, left, width, y)
+			})
+			y -= coverSubtitleSize * coverLeading
+		}
+	}
+
+	date := cover.Date
+	if date == "" && !doc.Info.CreationDate.IsZero() {
+		date = doc.Info.CreationDate.Format(coverDateLayout)
+	}
+	y = margins.Bottom
+	for _, line := range []string{date, cmp.Or(cover.Author, doc.Info.Author)} {
+		if line != "" {
+			drawCentered(page, line, fonts.regular, coverByLineSize, left, width, y)
+			y += coverByLineSize * coverLeading
+		}
+	}
+}
+
+// drawCentered draws text with its baseline at y, centered in the width
+// starting at left.
+func drawCentered(page *folio.Page, text string, f pageFont, size, left, width, y float64) {
+	x := left + (width-f.MeasureString(text, size))/2 //nolint:mnd // centered in the width
+	f.draw(page, text, size, x, y)
+}
+
+// drawGray runs draw, which adds text to page, with the fill color set to
+// the given gray level.
+func drawGray(page *folio.Page, gray float64, draw func()) {
+	cs := page.ContentStream()
+	if cs != nil {
+		cs.SaveState()
+		cs.SetFillColorGray(gray)
+	}
+	draw()
+	if cs != nil {
+		cs.RestoreState()
+	}
+}
+
+// wrapText breaks text into lines no wider than width, at spaces. Words
+// wider than width get a line of their own.
+func wrapText(text string, f pageFont, size, width float64) []string {
+	var lines []string
+	var line string
+	for _, word := range strings.Fields(text) {
+		candidate := strings.TrimSpace(line + " " + word)
+		if line != "" && f.MeasureString(candidate, size) > width {
+			lines = append(lines, line)
+			candidate = word
+		}
+		line = candidate
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
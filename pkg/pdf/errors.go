@@ -5,5 +5,32 @@ import "errors"
 // ErrUnknownPageSize is returned when an unrecognised page size identifier is supplied.
 var ErrUnknownPageSize = errors.New("unknown page size")
 
+// ErrInvalidPageSize is returned when explicit page dimensions cannot be parsed.
+var ErrInvalidPageSize = errors.New("invalid page size")
+
 // ErrInvalidMargin is returned when a margin string cannot be parsed.
 var ErrInvalidMargin = errors.New("invalid margin")
+
+// ErrUnknownOrientation is returned when a page orientation other than portrait or landscape is supplied.
+var ErrUnknownOrientation = errors.New("unknown page orientation")
+
+// ErrInvalidTemplate is returned when a running header or footer template is malformed.
+var ErrInvalidTemplate = errors.New("invalid header or footer template")
+
+// ErrInvalidSourceDateEpoch is returned when SOURCE_DATE_EPOCH is not a number of seconds.
+var ErrInvalidSourceDateEpoch = errors.New("invalid source date epoch")
+
+// ErrEmptyBook is returned when a book has no chapters.
+var ErrEmptyBook = errors.New("book has no chapters")
+
+// ErrInvalidCoverLogo is returned when the cover logo is not a readable PNG or JPEG image.
+var ErrInvalidCoverLogo = errors.New("invalid cover logo")
+
+// ErrInvalidContentsDepth is returned when the contents page depth is not a heading level.
+var ErrInvalidContentsDepth = errors.New("invalid contents page depth")
+
+// ErrInvalidFont is returned when a font is malformed or its file is not a readable TrueType or OpenType font.
+var ErrInvalidFont = errors.New("invalid font")
+
+// ErrInvalidWatermark is returned when the watermark style or image is invalid.
+var ErrInvalidWatermark = errors.New("invalid watermark")
@@ -0,0 +1,368 @@
+package pdf
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	folio "github.com/carlos7ags/folio/document"
+	"github.com/carlos7ags/folio/font"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Font is a font family given by its TrueType or OpenType files. Only
+// Regular is required; a missing style uses the closest one given: bold
+// italic the bold file, bold and italic the regular one.
+type Font struct {
+	Regular    string
+	Bold       string
+	Italic     string
+	BoldItalic string
+}
+
+// Fonts are the fonts embedded in the PDF instead of the standard PDF
+// fonts, subsetted to the glyphs used.
+type Fonts struct {
+	// Body is the fallback chain of the text fonts and Mono that of the
+	// code fonts: characters missing from the first font of a chain are
+	// drawn with the next font that has them. An empty chain keeps the
+	// standard fonts, Helvetica and Courier.
+	Body []Font
+	Mono []Font
+}
+
+// fontSeparator separates the files of the styles of a font in ParseFont.
+const fontSeparator = ","
+
+// Font styles, in the order of the files of ParseFont.
+const (
+	styleRegular = iota
+	styleBold
+	styleItalic
+	styleBoldItalic
+	styleCount
+)
+
+// fontFaceStyles are the CSS font-weight and font-style of each font style.
+var fontFaceStyles = [styleCount][2]string{
+	styleRegular:    {"normal", "normal"},
+	styleBold:       {"bold", "normal"},
+	styleItalic:     {"normal", "italic"},
+	styleBoldItalic: {"bold", "italic"},
+}
+
+// monoSelectors are the code elements drawn with the Mono fonts, as in
+// pdfFontOverrideCSS.
+var monoSelectors = []string{"body code", "body kbd", "body samp", "body tt", "body ." + preClass}
+
+// ParseFont parses a font written as the comma-separated paths of its
+// regular, bold, italic and bold italic files, e.g.
+// "Inter-Regular.ttf,Inter-Bold.ttf". Only the regular file is required;
+// empty paths are skipped.
+func ParseFont(s string) (Font, error) {
+	parts := strings.Split(s, fontSeparator)
+	if len(parts) > styleCount || strings.TrimSpace(parts[0]) == "" {
+		return Font{}, fmt.Errorf("%w: %q (expected REGULAR[,BOLD[,ITALIC[,BOLDITALIC]]])", ErrInvalidFont, s)
+	}
+	var files [styleCount]string
+	for i, p := range parts {
+		files[i] = strings.TrimSpace(p)
+	}
+	return Font{Regular: files[styleRegular], Bold: files[styleBold],
+		Italic: files[styleItalic], BoldItalic: files[styleBoldItalic]}, nil
+}
+
+// files returns the file of every style of f, filling in missing styles.
+func (f Font) files() [styleCount]string {
+	bold := cmp.Or(f.Bold, f.Regular)
+	return [styleCount]string{
+		styleRegular:    f.Regular,
+		styleBold:       bold,
+		styleItalic:     cmp.Or(f.Italic, f.Regular),
+		styleBoldItalic: cmp.Or(f.BoldItalic, bold),
+	}
+}
+
+// loadFonts reads and checks the font files of fonts. It returns fonts
+// with absolute paths and the content of each file by path.
+func loadFonts(fonts Fonts) (Fonts, map[string][]byte, error) {
+	data := make(map[string][]byte)
+	load := func(chain []Font) ([]Font, error) {
+		loaded := make([]Font, len(chain))
+		for i, f := range chain {
+			files := f.files()
+			for style, path := range files {
+				abs, err := filepath.Abs(path)
+				if err != nil {
+					return nil, fmt.Errorf("%w: %s: %w", ErrInvalidFont, path, err)
+				}
+				if _, ok := data[abs]; !ok {
+					b, err := os.ReadFile(abs) //nolint:gosec // reading the configured fonts is the point
+					if err != nil {
+						return nil, fmt.Errorf("%w: %w", ErrInvalidFont, err)
+					}
+					if _, err := font.ParseFont(b); err != nil {
+						return nil, fmt.Errorf("%w: %s: %w", ErrInvalidFont, path, err)
+					}
+					data[abs] = b
+				}
+				files[style] = abs
+			}
+			loaded[i] = Font{Regular: files[styleRegular], Bold: files[styleBold],
+				Italic: files[styleItalic], BoldItalic: files[styleBoldItalic]}
+		}
+		return loaded, nil
+	}
+	body, err := load(fonts.Body)
+	if err != nil {
+		return Fonts{}, nil, err
+	}
+	mono, err := load(fonts.Mono)
+	if err != nil {
+		return Fonts{}, nil, err
+	}
+	return Fonts{Body: body, Mono: mono}, data, nil
+}
+
+// fontChain is a fallback chain of fonts parsed for one document. font.Face
+// is not safe for concurrent use, so every document parses its own.
+type fontChain struct {
+	name  string // "body" or "mono", part of the CSS font families
+	fonts []Font
+	faces [][styleCount]font.Face
+}
+
+// fontRun is a piece of text drawn with one font of a chain.
+type fontRun struct {
+	text string
+	font int // index in the chain
+}
+
+// newFontChain parses the files of fonts, whose content is in data.
+func newFontChain(name string, fonts []Font, data map[string][]byte) fontChain {
+	fc := fontChain{name: name, fonts: fonts, faces: make([][styleCount]font.Face, len(fonts))}
+	parsed := make(map[string]font.Face)
+	for i, f := range fonts {
+		for style, path := range f.files() {
+			face, ok := parsed[path]
+			if !ok {
+				// The files were checked by loadFonts.
+				face, _ = font.ParseFont(data[path])
+				parsed[path] = face
+			}
+			fc.faces[i][style] = face
+		}
+	}
+	return fc
+}
+
+// family returns the CSS font family of the font at index i.
+func (fc fontChain) family(i int) string {
+	return "mdtohtml-" + fc.name + "-" + strconv.Itoa(i)
+}
+
+// fontFaces returns the @font-face rules of the fonts of fc.
+func (fc fontChain) fontFaces() string {
+	var sb strings.Builder
+	for i, f := range fc.fonts {
+		for style, path := range f.files() {
+			fmt.Fprintf(&sb, "@font-face { font-family: %q; font-weight: %s; font-style: %s; src: url(\"%s\"); }\n",
+				fc.family(i), fontFaceStyles[style][0], fontFaceStyles[style][1], path)
+		}
+	}
+	return sb.String()
+}
+
+// fontFor returns the index of the first font of fc with a glyph for r, or
+// 0 when none has one.
+func (fc fontChain) fontFor(r rune) int {
+	for i, faces := range fc.faces {
+		if faces[styleRegular].GlyphIndex(r) != 0 {
+			return i
+		}
+	}
+	return 0
+}
+
+// runs splits text into runs drawn with the same font of fc. White space
+// stays with the run it is in.
+func (fc fontChain) runs(text string) []fontRun {
+	var runs []fontRun
+	start, current := 0, 0
+	for i, r := range text {
+		f := current
+		if !unicode.IsSpace(r) {
+			f = fc.fontFor(r)
+		}
+		if f != current && i > start {
+			runs = append(runs, fontRun{text: text[start:i], font: current})
+			start = i
+		}
+		current = f
+	}
+	if start < len(text) {
+		runs = append(runs, fontRun{text: text[start:], font: current})
+	}
+	return runs
+}
+
+// fontSet holds the fonts of the body and of the code of a document.
+type fontSet struct {
+	body, mono fontChain
+	embedded   [styleCount][]*font.EmbeddedFont // the body fonts drawn by pageFont, by style
+}
+
+// newFontSet parses the fonts of c for a new document.
+func (c *Converter) newFontSet() *fontSet {
+	return &fontSet{
+		body: newFontChain("body", c.pdfOpts.Fonts.Body, c.fontData),
+		mono: newFontChain("mono", c.pdfOpts.Fonts.Mono, c.fontData),
+	}
+}
+
+// css returns the stylesheet declaring the fonts of fs and applying the
+// first font of each chain to the body and code.
+func (fs *fontSet) css() string {
+	var sb strings.Builder
+	sb.WriteString(fs.body.fontFaces())
+	sb.WriteString(fs.mono.fontFaces())
+	if len(fs.body.fonts) > 0 {
+		fmt.Fprintf(&sb, "body { font-family: %q; }\n", fs.body.family(0))
+	}
+	if len(fs.mono.fonts) > 0 {
+		fmt.Fprintf(&sb, "%s { font-family: %q; }\n", strings.Join(monoSelectors, ", "), fs.mono.family(0))
+	}
+	return sb.String()
+}
+
+// isZero reports whether fs has no fonts.
+func (fs *fontSet) isZero() bool {
+	return len(fs.body.fonts) == 0 && len(fs.mono.fonts) == 0
+}
+
+// apply adds the stylesheet of fs to the head of the document root and
+// wraps the text that the first font of its chain cannot draw in spans
+// with the fallback font that can. The stylesheet is added even without
+// the default CSS, so the fonts also apply with converter.Options.NoCSS.
+func (fs *fontSet) apply(root *html.Node) {
+	if fs.isZero() {
+		return
+	}
+	var head *html.Node
+	walkElements(root, func(n *html.Node) {
+		if head == nil && n.DataAtom == atom.Head {
+			head = n
+		}
+	})
+	if head != nil {
+		style := &html.Node{Type: html.ElementNode, Data: "style", DataAtom: atom.Style}
+		style.AppendChild(&html.Node{Type: html.TextNode, Data: fs.css()})
+		head.AppendChild(style)
+	}
+
+	var walk func(n *html.Node, chain fontChain)
+	walk = func(n *html.Node, chain fontChain) {
+		for c := n.FirstChild; c != nil; {
+			next := c.NextSibling
+			switch {
+			case c.Type == html.TextNode && len(chain.fonts) > 1:
+				fs.wrapFallbacks(c, chain)
+			case c.Type != html.ElementNode:
+			case c.DataAtom == atom.Head, c.DataAtom == atom.Script, c.DataAtom == atom.Style, c.DataAtom == atom.Svg:
+			case c.DataAtom == atom.Code, c.DataAtom == atom.Kbd, c.DataAtom == atom.Samp,
+				c.DataAtom == atom.Tt, c.DataAtom == atom.Pre,
+				slices.Contains(strings.Fields(attrValue(c, "class")), preClass):
+				walk(c, fs.mono)
+			default:
+				walk(c, chain)
+			}
+			c = next
+		}
+	}
+	walk(root, fs.body)
+}
+
+// wrapFallbacks replaces the text node n with the runs of its text, those
+// of fallback fonts of chain wrapped in spans selecting the font.
+func (fs *fontSet) wrapFallbacks(n *html.Node, chain fontChain) {
+	runs := chain.runs(n.Data)
+	if len(runs) == 1 && runs[0].font == 0 {
+		return
+	}
+	for _, run := range runs {
+		text := &html.Node{Type: html.TextNode, Data: run.text}
+		if run.font == 0 {
+			n.Parent.InsertBefore(text, n)
+			continue
+		}
+		span := &html.Node{Type: html.ElementNode, Data: "span", DataAtom: atom.Span,
+			Attr: []html.Attribute{{Key: "style", Val: "font-family: " + strconv.Quote(chain.family(run.font))}}}
+		span.AppendChild(text)
+		n.Parent.InsertBefore(span, n)
+	}
+	n.Parent.RemoveChild(n)
+}
+
+// pageFonts are the regular and bold fonts drawing text directly on pages.
+type pageFonts struct {
+	regular, bold pageFont
+}
+
+// pageFonts returns the fonts drawing text directly on pages: the body
+// fonts of fs, or Helvetica when there are none.
+func (fs *fontSet) pageFonts() pageFonts {
+	return pageFonts{
+		regular: fs.pageFont(styleRegular, font.Helvetica),
+		bold:    fs.pageFont(styleBold, font.HelveticaBold),
+	}
+}
+
+// pageFont returns the font drawing text directly on pages, outside of the
+// HTML layout, in the given style: the body fonts of fs, or std when there
+// are none.
+func (fs *fontSet) pageFont(style int, std *font.Standard) pageFont {
+	if fs.embedded[style] == nil {
+		for _, faces := range fs.body.faces {
+			fs.embedded[style] = append(fs.embedded[style], font.NewEmbeddedFont(faces[style]))
+		}
+	}
+	return pageFont{std: std, chain: fs.body, embedded: fs.embedded[style]}
+}
+
+// pageFont draws text directly on pages with a standard font, or with
+// the embedded fonts of a chain.
+type pageFont struct {
+	std      *font.Standard
+	chain    fontChain
+	embedded []*font.EmbeddedFont // the fonts of chain in the style drawn, if any
+}
+
+// MeasureString returns the width of text at size.
+func (f pageFont) MeasureString(text string, size float64) float64 {
+	if len(f.embedded) == 0 {
+		return f.std.MeasureString(text, size)
+	}
+	width := 0.0
+	for _, run := range f.chain.runs(text) {
+		width += f.embedded[run.font].MeasureString(run.text, size)
+	}
+	return width
+}
+
+// draw draws text on page with its baseline starting at x, y.
+func (f pageFont) draw(page *folio.Page, text string, size, x, y float64) {
+	if len(f.embedded) == 0 {
+		page.AddText(text, f.std, size, x, y)
+		return
+	}
+	for _, run := range f.chain.runs(text) {
+		page.AddTextEmbedded(run.text, f.embedded[run.font], size, x, y)
+		x += f.embedded[run.font].MeasureString(run.text, size)
+	}
+}
@@ -0,0 +1,169 @@
+package pdf
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	folio "github.com/carlos7ags/folio/document"
+	"github.com/carlos7ags/folio/layout"
+)
+
+// Placeholders expanded in running header and footer templates.
+const (
+	PlaceholderTitle    = "{title}"    // the document title
+	PlaceholderPage     = "{page}"     // the current page number
+	PlaceholderPages    = "{pages}"    // the number of pages
+	PlaceholderDate     = "{date}"     // the conversion date, as 2006-01-02
+	PlaceholderFilename = "{filename}" // the base name of the input file
+)
+
+// slotSeparator separates the left, center and right slots of a template.
+const slotSeparator = "|"
+
+// Running header and footer appearance, matching folio's @page margin boxes.
+const (
+	runningFontSize = 9.0
+	runningGray     = 0.4
+)
+
+// placeholderPattern matches a placeholder in a template.
+var placeholderPattern = regexp.MustCompile(`\{[^{}]*\}`)
+
+// RunningText is the content of a running header or footer: a template for
+// each slot of the page margin. Empty slots draw nothing.
+type RunningText struct {
+	Left   string
+	Center string
+	Right  string
+}
+
+// ParseRunningText splits a template into slots at "|": a template without
+// "|" is centered, "left|right" fills the outer slots and
+// "left|center|right" all three.
+func ParseRunningText(template string) (RunningText, error) {
+	parts := strings.Split(template, slotSeparator)
+	var rt RunningText
+	switch len(parts) {
+	case 1:
+		rt.Center = parts[0]
+	case 2: //nolint:mnd // left|right
+		rt.Left, rt.Right = parts[0], parts[1]
+	case 3: //nolint:mnd // left|center|right
+		rt.Left, rt.Center, rt.Right = parts[0], parts[1], parts[2]
+	default:
+		return RunningText{}, fmt.Errorf("%w: %q has more than three slots", ErrInvalidTemplate, template)
+	}
+	for _, slot := range rt.slots() {
+		for _, p := range placeholderPattern.FindAllString(slot, -1) {
+			if !slices.Contains(placeholders, p) {
+				return RunningText{}, fmt.Errorf("%w: unknown placeholder %s in %q (use %s)",
+					ErrInvalidTemplate, p, template, strings.Join(placeholders, ", "))
+			}
+		}
+	}
+	return rt, nil
+}
+
+// IsZero reports whether rt draws nothing.
+func (rt RunningText) IsZero() bool {
+	return rt == RunningText{}
+}
+
+// Indexes of the slots returned by RunningText.slots.
+const (
+	slotLeft = iota
+	slotCenter
+	slotRight
+)
+
+// slots returns the left, center and right templates.
+func (rt RunningText) slots() [3]string {
+	return [3]string{rt.Left, rt.Center, rt.Right}
+}
+
+// placeholders lists the supported placeholders.
+var placeholders = []string{PlaceholderTitle, PlaceholderPage, PlaceholderPages, PlaceholderDate, PlaceholderFilename}
+
+// runningValues holds the per-document placeholder values.
+type runningValues struct {
+	title    string
+	date     string
+	filename string
+}
+
+// newRunningValues returns the placeholder values for the document titled
+// title converted from inputPath, which is empty when there is no file.
+func newRunningValues(title, inputPath string, now time.Time) runningValues {
+	v := runningValues{title: title, date: now.Format(time.DateOnly)}
+	if inputPath != "" {
+		v.filename = filepath.Base(inputPath)
+	}
+	return v
+}
+
+// expand replaces the placeholders of template for the page at 0-based
+// index of total pages.
+func (v runningValues) expand(template string, index, total int) string {
+	return strings.NewReplacer(
+		PlaceholderTitle, v.title,
+		PlaceholderPage, strconv.Itoa(index+1),
+		PlaceholderPages, strconv.Itoa(total),
+		PlaceholderDate, v.date,
+		PlaceholderFilename, v.filename,
+	).Replace(template)
+}
+
+// runningText returns the decorators drawing header and footer on the pages
+// from index first on, e.g. after a cover page, in the middle of the top
+// and bottom margins. skipFirst also leaves page first blank. A decorator
+// is nil when its text is zero.
+func runningText(header, footer RunningText, first int, skipFirst bool, f pageFont,
+	pageSize folio.PageSize, margins layout.Margins, values runningValues) (folio.PageDecorator, folio.PageDecorator) {
+	decorator := func(rt RunningText, baseline float64) folio.PageDecorator {
+		return func(ctx folio.PageContext, page *folio.Page) {
+			if ctx.PageIndex < first || skipFirst && ctx.PageIndex == first {
+				return
+			}
+			left := margins.Left
+			right := pageSize.Width - margins.Right
+			for i, tmpl := range rt.slots() {
+				text := values.expand(tmpl, ctx.PageIndex, ctx.TotalPages)
+				if text == "" {
+					continue
+				}
+				width := f.MeasureString(text, runningFontSize)
+				var x float64
+				switch i {
+				case slotLeft:
+					x = left
+				case slotCenter:
+					x = (left + right - width) / 2 //nolint:mnd // centered between the margins
+				case slotRight:
+					x = right - width
+				}
+				drawGrayText(page, f, text, x, baseline)
+			}
+		}
+	}
+	// Baselines vertically center the text in the margins.
+	var top, bottom folio.PageDecorator
+	if !header.IsZero() {
+		top = decorator(header, pageSize.Height-(margins.Top+runningFontSize)/2) //nolint:mnd // see above
+	}
+	if !footer.IsZero() {
+		bottom = decorator(footer, (margins.Bottom-runningFontSize)/2) //nolint:mnd // see above
+	}
+	return top, bottom
+}
+
+// drawGrayText draws text at x, y in the running header and footer style.
+func drawGrayText(page *folio.Page, f pageFont, text string, x, y float64) {
+	drawGray(page, runningGray, func() {
+		f.draw(page, text, runningFontSize, x, y)
+	})
+}
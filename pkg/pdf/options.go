@@ -4,13 +4,12 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/carlos7ags/folio/document"
 )
 
-// PageSizeA4 and PageSizeLetter are the recognised string identifiers for
-// the most common page sizes. Additional folio sizes can be added here as
-// they are needed.
+// Identifiers of common page sizes. PageSizes lists every recognised name.
 const (
 	PageSizeA4      = "A4"
 	PageSizeLetter  = "Letter"
@@ -20,6 +19,22 @@ const (
 	PageSizeTabloid = "Tabloid"
 )
 
+// Page orientations accepted by Options.Orientation.
+const (
+	OrientationPortrait  = "portrait"
+	OrientationLandscape = "landscape"
+)
+
+// Default document information of the generated PDFs.
+const (
+	DefaultCreator  = "mdtohtml"
+	DefaultProducer = "mdtohtml (folio)"
+)
+
+// SourceDateEpochEnv is the environment variable that, following the
+// reproducible builds convention, fixes the creation date of the PDFs.
+const SourceDateEpochEnv = "SOURCE_DATE_EPOCH"
+
 // DefaultMargin is the default page margin in PDF points (1.25 inch).
 // 72 points = 1 inch, so 90 points = 1.25 inch.
 const DefaultMargin = 90.0
@@ -41,12 +56,66 @@ type Margins struct {
 
 // Options configures PDF generation.
 type Options struct {
-	// PageSize is the page size identifier (e.g. "A4", "Letter").
-	// Empty defaults to A4.
+	// PageSize is a page size name (e.g. "A4", "B5", "Letter"; see
+	// PageSizes) or explicit dimensions written WIDTHxHEIGHT with the units
+	// of ParseMargin (e.g. "210mmx148mm", "6inx9in"). Empty defaults to A4.
 	PageSize string
 
 	// Margins are the page margins applied unless overridden by an @page CSS rule.
 	Margins Margins
+
+	// Orientation is OrientationPortrait or OrientationLandscape, which turn
+	// the page size so its long side is vertical or horizontal. Empty keeps
+	// the page size as given: portrait for the named sizes except Ledger.
+	Orientation string
+
+	// Header and Footer are drawn in the top and bottom margin of every
+	// page; see ParseRunningText. They are drawn in addition to any @page
+	// margin boxes of the stylesheet.
+	Header RunningText
+	Footer RunningText
+
+	// HeaderFooterSkipFirst leaves the first page, e.g. a title page,
+	// without header and footer.
+	HeaderFooterSkipFirst bool
+
+	// Author, Subject and Keywords are written to the PDF document
+	// information. Empty fields fall back to the front matter author,
+	// description and keywords.
+	Author   string
+	Subject  string
+	Keywords string
+
+	// Creator and Producer name the applications that wrote the Markdown
+	// and the PDF. Empty fields default to DefaultCreator and
+	// DefaultProducer.
+	Creator  string
+	Producer string
+
+	// CreationDate is written to the PDF document information and is the
+	// value of the {date} placeholder. Zero uses the time of the
+	// conversion; see SourceDateEpoch for reproducible output.
+	CreationDate time.Time
+
+	// Cover is the cover page drawn before the document. Running headers
+	// and footers leave it blank.
+	Cover Cover
+
+	// ContentsPage draws a contents page after the cover, listing the
+	// headings that become bookmarks with their page numbers.
+	ContentsPage bool
+
+	// ContentsDepth is the deepest heading level on the contents page.
+	// Zero defaults to DefaultContentsDepth.
+	ContentsDepth int
+
+	// Fonts are TrueType or OpenType fonts embedded for the text and code,
+	// the cover, contents page, header and footer included. Zero keeps the
+	// standard PDF fonts, which only draw Latin text.
+	Fonts Fonts
+
+	// Watermark is drawn on every page, e.g. to mark drafts; see Watermark.
+	Watermark Watermark
 }
 
 // DefaultOptions returns sensible PDF defaults (A4, 1.25 inch margins).
@@ -69,14 +138,19 @@ func ParseMargin(s string) (float64, error) {
 	if s == "" {
 		return DefaultMargin, nil
 	}
+	return parseLength(s, ErrInvalidMargin)
+}
 
+// parseLength converts a non-negative length with an optional unit suffix
+// (pt, in, cm, mm) into PDF points. Errors wrap errKind.
+func parseLength(s string, errKind error) (float64, error) {
 	num, unit := splitMargin(s)
 	v, err := strconv.ParseFloat(num, 64)
 	if err != nil {
-		return 0, fmt.Errorf("%w: %s", ErrInvalidMargin, s)
+		return 0, fmt.Errorf("%w: %s", errKind, s)
 	}
 	if v < 0 {
-		return 0, fmt.Errorf("%w: %s (must be non-negative)", ErrInvalidMargin, s)
+		return 0, fmt.Errorf("%w: %s (must be non-negative)", errKind, s)
 	}
 
 	switch unit {
@@ -89,7 +163,40 @@ func ParseMargin(s string) (float64, error) {
 	case "mm":
 		return v * pointsPerMm, nil
 	default:
-		return 0, fmt.Errorf("%w: unknown unit %q", ErrInvalidMargin, unit)
+		return 0, fmt.Errorf("%w: unknown unit %q", errKind, unit)
+	}
+}
+
+// ParseMargins converts a margin string into per-side Margins. Like the CSS
+// margin shorthand it accepts one to four space-separated values: one applies
+// to every side, two are top/bottom and right/left, three are top,
+// right/left and bottom, four are top, right, bottom and left. Each value is
+// parsed by ParseMargin. Empty input returns DefaultMargin on every side.
+func ParseMargins(s string) (Margins, error) {
+	values := strings.Fields(s)
+	if len(values) > 4 { //nolint:mnd // the CSS shorthand has at most four values
+		return Margins{}, fmt.Errorf("%w: %s (expected one to four values)", ErrInvalidMargin, s)
+	}
+	sides := make([]float64, len(values))
+	for i, v := range values {
+		m, err := ParseMargin(v)
+		if err != nil {
+			return Margins{}, err
+		}
+		sides[i] = m
+	}
+
+	switch len(sides) {
+	case 0:
+		return Margins{Top: DefaultMargin, Right: DefaultMargin, Bottom: DefaultMargin, Left: DefaultMargin}, nil
+	case 1:
+		return Margins{Top: sides[0], Right: sides[0], Bottom: sides[0], Left: sides[0]}, nil
+	case 2: //nolint:mnd // vertical, horizontal
+		return Margins{Top: sides[0], Right: sides[1], Bottom: sides[0], Left: sides[1]}, nil
+	case 3: //nolint:mnd // top, horizontal, bottom
+		return Margins{Top: sides[0], Right: sides[1], Bottom: sides[2], Left: sides[1]}, nil
+	default:
+		return Margins{Top: sides[0], Right: sides[1], Bottom: sides[2], Left: sides[3]}, nil
 	}
 }
 
@@ -105,23 +212,40 @@ func splitMargin(s string) (string, string) {
 	return s, ""
 }
 
-// resolvePageSize maps a case-insensitive identifier to a folio PageSize.
-// Returns an error for unknown identifiers.
-func resolvePageSize(name string) (document.PageSize, error) {
-	switch strings.ToLower(strings.TrimSpace(name)) {
-	case "", "a4":
-		return document.PageSizeA4, nil
-	case "letter":
-		return document.PageSizeLetter, nil
-	case "legal":
-		return document.PageSizeLegal, nil
-	case "a3":
-		return document.PageSizeA3, nil
-	case "a5":
-		return document.PageSizeA5, nil
-	case "tabloid":
-		return document.PageSizeTabloid, nil
+// orientPageSize turns ps to the named orientation, swapping its dimensions
+// when they do not match it. An empty orientation leaves ps unchanged.
+// Returns an error for unknown orientations.
+func orientPageSize(ps document.PageSize, orientation string) (document.PageSize, error) {
+	switch strings.ToLower(strings.TrimSpace(orientation)) {
+	case "":
+		return ps, nil
+	case OrientationPortrait:
+		if ps.Width > ps.Height {
+			return ps.Landscape(), nil
+		}
+		return ps, nil
+	case OrientationLandscape:
+		if ps.Width < ps.Height {
+			return ps.Landscape(), nil
+		}
+		return ps, nil
 	default:
-		return document.PageSize{}, fmt.Errorf("%w: %s", ErrUnknownPageSize, name)
+		return document.PageSize{}, fmt.Errorf("%w: %s (use portrait or landscape)", ErrUnknownOrientation, orientation)
+	}
+}
+
+// SourceDateEpoch parses the value of the SOURCE_DATE_EPOCH environment
+// variable, a count of seconds since the Unix epoch, into a UTC time. Empty
+// input returns the zero time, so the conversion time is used.
+func SourceDateEpoch(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, nil
+	}
+	sec, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || sec < 0 {
+		return time.Time{}, fmt.Errorf("%w: %s=%q (expected seconds since 1970-01-01)",
+			ErrInvalidSourceDateEpoch, SourceDateEpochEnv, s)
 	}
+	return time.Unix(sec, 0).UTC(), nil
 }
@@ -0,0 +1,145 @@
+package pdf
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/carlos7ags/folio/document"
+)
+
+// isoSeries holds the portrait dimensions in millimetres of ISO 216 (A, B)
+// and ISO 269 (C) sizes 0 to 10, indexed by size number.
+var isoSeries = []struct {
+	letter string
+	sizes  [11][2]float64
+}{
+	{"A", [11][2]float64{
+		{841, 1189}, {594, 841}, {420, 594}, {297, 420}, {210, 297}, {148, 210},
+		{105, 148}, {74, 105}, {52, 74}, {37, 52}, {26, 37},
+	}},
+	{"B", [11][2]float64{
+		{1000, 1414}, {707, 1000}, {500, 707}, {353, 500}, {250, 353}, {176, 250},
+		{125, 176}, {88, 125}, {62, 88}, {44, 62}, {31, 44},
+	}},
+	{"C", [11][2]float64{
+		{917, 1297}, {648, 917}, {458, 648}, {324, 458}, {229, 324}, {162, 229},
+		{114, 162}, {81, 114}, {57, 81}, {40, 57}, {28, 40},
+	}},
+}
+
+// usSizes holds the North American paper sizes in inches, in the order they
+// are listed to users.
+var usSizes = []struct {
+	name          string
+	width, height float64
+}{
+	{PageSizeLetter, 8.5, 11},
+	{PageSizeLegal, 8.5, 14},
+	{PageSizeTabloid, 11, 17},
+	{"Ledger", 17, 11},
+	{"Executive", 7.25, 10.5},
+	{"Statement", 5.5, 8.5},
+}
+
+// PageSizes returns the recognised page size names: the ISO A, B and C
+// series from 0 to 10 followed by the North American sizes.
+func PageSizes() []string {
+	var names []string
+	for _, s := range isoSeries {
+		for i := range s.sizes {
+			names = append(names, s.letter+strconv.Itoa(i))
+		}
+	}
+	for _, s := range usSizes {
+		names = append(names, s.name)
+	}
+	return names
+}
+
+// resolvePageSize maps a case-insensitive page size name, or explicit
+// dimensions written WIDTHxHEIGHT with the units of ParseMargin (e.g.
+// "210mmx148mm", "6inx9in"), to a folio PageSize. Empty defaults to A4.
+func resolvePageSize(name string) (document.PageSize, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = PageSizeA4
+	}
+	if ps, ok := namedPageSize(name); ok {
+		return ps, nil
+	}
+	if w, h, ok := strings.Cut(strings.ToLower(name), "x"); ok && startsWithNumber(w) {
+		return parsePageDimensions(name, w, h)
+	}
+	return document.PageSize{}, fmt.Errorf("%w: %q (use WIDTHxHEIGHT such as 210mmx148mm, "+
+		"or one of A0-A10, B0-B10, C0-C10, %s)", ErrUnknownPageSize, name, usSizeNames())
+}
+
+// namedPageSize returns the size called name, ignoring case. ISO sizes are
+// converted from millimetres and rounded to hundredths of a point, matching
+// folio's own constants.
+func namedPageSize(name string) (document.PageSize, bool) {
+	for _, s := range usSizes {
+		if strings.EqualFold(name, s.name) {
+			return document.PageSize{Width: s.width * pointsPerInch, Height: s.height * pointsPerInch}, true
+		}
+	}
+	for _, s := range isoSeries {
+		if len(name) < 2 || !strings.EqualFold(name[:1], s.letter) {
+			continue
+		}
+		n, err := strconv.Atoi(name[1:])
+		if err != nil || n < 0 || n >= len(s.sizes) || name[1] == '+' {
+			return document.PageSize{}, false
+		}
+		return document.PageSize{
+			Width:  roundPoints(s.sizes[n][0] * pointsPerMm),
+			Height: roundPoints(s.sizes[n][1] * pointsPerMm),
+		}, true
+	}
+	return document.PageSize{}, false
+}
+
+// parsePageDimensions parses the width w and height h of the custom page
+// size spec.
+func parsePageDimensions(spec, w, h string) (document.PageSize, error) {
+	var dims [2]float64
+	for i, v := range []string{w, h} {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			return document.PageSize{}, fmt.Errorf("%w: %q (expected WIDTHxHEIGHT, e.g. 6inx9in)", ErrInvalidPageSize, spec)
+		}
+		d, err := parseLength(v, ErrInvalidPageSize)
+		if err != nil {
+			return document.PageSize{}, fmt.Errorf("%w in %q", err, spec)
+		}
+		if d == 0 {
+			return document.PageSize{}, fmt.Errorf("%w: %q (width and height must be positive)", ErrInvalidPageSize, spec)
+		}
+		dims[i] = d
+	}
+	return document.PageSize{Width: dims[0], Height: dims[1]}, nil
+}
+
+// startsWithNumber reports whether s begins like a length, e.g. "6in" or
+// ".5in".
+func startsWithNumber(s string) bool {
+	s = strings.TrimSpace(s)
+	return s != "" && strings.ContainsRune("0123456789.-+", rune(s[0]))
+}
+
+// usSizeNames lists the North American size names for error messages.
+func usSizeNames() string {
+	names := make([]string, len(usSizes))
+	for i, s := range usSizes {
+		names[i] = s.name
+	}
+	return strings.Join(names, ", ")
+}
+
+// roundPoints rounds v to hundredths of a point.
+func roundPoints(v float64) float64 {
+	const hundredths = 100
+	return math.Round(v*hundredths) / hundredths
+}
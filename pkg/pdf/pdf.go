@@ -5,13 +5,18 @@ package pdf
 
 import (
 	"bytes"
+	"cmp"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	folio "github.com/carlos7ags/folio/document"
 	folioHTML "github.com/carlos7ags/folio/html"
+	folioimage "github.com/carlos7ags/folio/image"
 	"github.com/carlos7ags/folio/layout"
+	"golang.org/x/net/html"
 
 	"github.com/sgaunet/mdtohtml/pkg/converter"
 )
@@ -45,6 +50,31 @@ body pre > code {
 }
 `
 
+// pdfTOCCSS lays out the flat table of contents entries (see
+// converter.TOCOptions.Flat). Anchors must be block-level for folio to turn
+// them into in-document links, and each nesting depth is indented by 18pt.
+const pdfTOCCSS = `
+nav.toc a { display: block; }
+nav.toc .toc-depth-2 { padding-left: 18pt; }
+nav.toc .toc-depth-3 { padding-left: 36pt; }
+nav.toc .toc-depth-4 { padding-left: 54pt; }
+nav.toc .toc-depth-5 { padding-left: 72pt; }
+nav.toc .toc-depth-6 { padding-left: 90pt; }
+`
+
+// pdfAlertCSS restyles GitHub alerts for folio, which does not apply the
+// border-left-color longhand the GitHub stylesheet uses to colour each alert
+// type. Lengths are restated in points.
+const pdfAlertCSS = `
+body .markdown-alert { padding: 4pt 12pt; border-left: 3pt solid #d0d7de; }
+body .markdown-alert .markdown-alert-title { font-weight: bold; }
+body .markdown-alert.markdown-alert-note { border-left: 3pt solid #0969da; }
+body .markdown-alert.markdown-alert-tip { border-left: 3pt solid #1a7f37; }
+body .markdown-alert.markdown-alert-important { border-left: 3pt solid #8250df; }
+body .markdown-alert.markdown-alert-warning { border-left: 3pt solid #9a6700; }
+body .markdown-alert.markdown-alert-caution { border-left: 3pt solid #d1242f; }
+`
+
 // Converter renders Markdown directly to PDF. It satisfies converter.Converter
 // so it can be plugged into the existing batch processor without any changes
 // to the file-walking or path logic.
@@ -52,19 +82,57 @@ type Converter struct {
 	htmlConv *converter.CompleteConverter
 	pageSize folio.PageSize
 	margins  layout.Margins
+	pdfOpts  Options
+
+	coverLogo *folioimage.Image // the loaded Cover.Logo, or nil
+	fontData  map[string][]byte // the content of the files of Options.Fonts
+
+	watermarkColor [3]float64        // the parsed Watermark.Color
+	watermarkImage *folioimage.Image // the loaded Watermark.Image, or nil
 }
 
 // New builds a PDF converter from the same options the HTML pipeline uses,
-// plus PDF-specific options (page size, margins).
+// plus PDF-specific options (page size, orientation, margins, cover page),
+// loading the cover logo, fonts and watermark image. Syntax highlighting
+// always uses inline styles so code colours survive without a stylesheet
+// lookup, and the table of contents is rendered flat so its links stay
+// clickable.
 func New(opts converter.Options, pdfOpts Options) (*Converter, error) {
 	ps, err := resolvePageSize(pdfOpts.PageSize)
 	if err != nil {
 		return nil, err
 	}
+	if ps, err = orientPageSize(ps, pdfOpts.Orientation); err != nil {
+		return nil, err
+	}
+	if pdfOpts.ContentsDepth < 0 || pdfOpts.ContentsDepth > maxHeadingLevel {
+		return nil, fmt.Errorf("%w: %d (use 1 to %d)", ErrInvalidContentsDepth, pdfOpts.ContentsDepth, maxHeadingLevel)
+	}
+	var logo *folioimage.Image
+	if pdfOpts.Cover.Enabled && pdfOpts.Cover.Logo != "" {
+		if logo, err = loadImage(pdfOpts.Cover.Logo, ErrInvalidCoverLogo); err != nil {
+			return nil, err
+		}
+	}
+	fonts, fontData, err := loadFonts(pdfOpts.Fonts)
+	if err != nil {
+		return nil, err
+	}
+	pdfOpts.Fonts = fonts
+	watermarkColor, watermarkImage, err := loadWatermark(pdfOpts.Watermark)
+	if err != nil {
+		return nil, err
+	}
+	opts.HighlightCSSClasses = false
+	opts.TOC.Flat = true
+	overrideCSS := pdfFontOverrideCSS + pdfAlertCSS
+	if opts.TOC.Enabled {
+		overrideCSS += pdfTOCCSS
+	}
 	if opts.AdditionalCSS == "" {
-		opts.AdditionalCSS = pdfFontOverrideCSS
+		opts.AdditionalCSS = overrideCSS
 	} else {
-		opts.AdditionalCSS = opts.AdditionalCSS + "\n" + pdfFontOverrideCSS
+		opts.AdditionalCSS = opts.AdditionalCSS + "\n" + overrideCSS
 	}
 	return &Converter{
 		htmlConv: converter.NewCompleteConverter(opts),
@@ -75,6 +143,12 @@ func New(opts converter.Options, pdfOpts Options) (*Converter, error) {
 			Bottom: pdfOpts.Margins.Bottom,
 			Left:   pdfOpts.Margins.Left,
 		},
+		pdfOpts:   pdfOpts,
+		coverLogo: logo,
+		fontData:  fontData,
+
+		watermarkColor: watermarkColor,
+		watermarkImage: watermarkImage,
 	}, nil
 }
 
@@ -103,24 +177,38 @@ func (c *Converter) Convert(input []byte) ([]byte, error) {
 // input file's directory is wired into folio's BasePath so relative image
 // references (e.g. ./img/foo.png) resolve correctly.
 func (c *Converter) ConvertFile(inputPath, outputPath string) error {
-	input, err := os.ReadFile(inputPath)
+	input, err := readInput(inputPath)
 	if err != nil {
-		if os.IsPermission(err) {
-			return fmt.Errorf("permission denied reading file '%s': %w", inputPath, err)
-		}
-		return fmt.Errorf("error reading file '%s': %w", inputPath, err)
+		return err
 	}
 
-	htmlBytes, err := c.htmlConv.Convert(input)
+	htmlBytes, err := c.htmlConv.ConvertSource(input, inputPath)
 	if err != nil {
 		return fmt.Errorf("markdown to HTML: %w", err)
 	}
 
-	doc, err := c.renderPDF(string(htmlBytes), filepath.Dir(inputPath))
+	doc, err := c.renderPDF(string(htmlBytes), inputPath)
 	if err != nil {
 		return err
 	}
 
+	return saveDocument(doc, outputPath)
+}
+
+// readInput reads the Markdown file at inputPath.
+func readInput(inputPath string) ([]byte, error) {
+	input, err := os.ReadFile(inputPath)
+	if err != nil {
+		if os.IsPermission(err) {
+			return nil, fmt.Errorf("permission denied reading file '%s': %w", inputPath, err)
+		}
+		return nil, fmt.Errorf("error reading file '%s': %w", inputPath, err)
+	}
+	return input, nil
+}
+
+// saveDocument writes doc to outputPath.
+func saveDocument(doc *folio.Document, outputPath string) error {
 	if err := doc.Save(outputPath); err != nil {
 		if os.IsPermission(err) {
 			return fmt.Errorf("permission denied writing file '%s': %w", outputPath, err)
@@ -130,36 +218,147 @@ func (c *Converter) ConvertFile(inputPath, outputPath string) error {
 	return nil
 }
 
-// renderPDF runs folio's HTML→PDF stage, applying any @page configuration
-// found in the source HTML and forwarding the document title metadata.
-func (c *Converter) renderPDF(htmlStr, basePath string) (*folio.Document, error) {
-	result, err := folioHTML.ConvertFull(htmlStr, &folioHTML.Options{BasePath: basePath})
-	if err != nil {
-		return nil, fmt.Errorf("HTML to PDF: %w", err)
+// setInfo fills the document information of doc from the options, falling
+// back to the metadata the HTML stage emitted from front matter.
+func (c *Converter) setInfo(doc *folio.Document, meta folioHTML.DocMetadata) {
+	doc.Info.Title = meta.Title
+	doc.Info.Author = cmp.Or(c.pdfOpts.Author, meta.Author)
+	doc.Info.Subject = cmp.Or(c.pdfOpts.Subject, meta.Subject, meta.Description)
+	doc.Info.Keywords = cmp.Or(c.pdfOpts.Keywords, meta.Keywords)
+	doc.Info.Creator = cmp.Or(c.pdfOpts.Creator, DefaultCreator)
+	doc.Info.Producer = cmp.Or(c.pdfOpts.Producer, DefaultProducer)
+	doc.Info.CreationDate = c.pdfOpts.CreationDate
+	if doc.Info.CreationDate.IsZero() {
+		doc.Info.CreationDate = time.Now()
 	}
+}
 
+// newDocument returns an empty document with the page size of c, the
+// margins of c unless the stylesheet of result has an @page rule with
+// margins, and the @page margin boxes of result. It also returns the margins.
+func (c *Converter) newDocument(result *folioHTML.ConvertResult) (*folio.Document, layout.Margins) {
 	doc := folio.NewDocument(c.pageSize)
-	doc.SetMargins(c.margins)
+	margins := c.margins
 	if pc := result.PageConfig; pc != nil && pc.HasMargins {
-		doc.SetMargins(layout.Margins{
+		margins = layout.Margins{
 			Top:    pc.MarginTop,
 			Right:  pc.MarginRight,
 			Bottom: pc.MarginBottom,
 			Left:   pc.MarginLeft,
-		})
+		}
 	}
+	doc.SetMargins(margins)
 	if result.MarginBoxes != nil {
 		doc.SetMarginBoxes(result.MarginBoxes)
 	}
 	if result.FirstMarginBoxes != nil {
 		doc.SetFirstMarginBoxes(result.FirstMarginBoxes)
 	}
+	return doc, margins
+}
+
+// renderPDF runs folio's HTML→PDF stage, applying any @page configuration
+// found in the source HTML and filling the document information (see
+// setInfo), and adds the cover and contents pages (see addFrontPages).
+// Heading ids become named destinations so in-document links are clickable,
+// code blocks are flattened (see flattenCode) and the configured fonts are
+// applied (see fontSet.apply).
+// inputPath is the Markdown file, against whose directory relative images
+// resolve; it is empty when there is none.
+func (c *Converter) renderPDF(htmlStr, inputPath string) (*folio.Document, error) {
+	basePath := ""
+	if inputPath != "" {
+		basePath = filepath.Dir(inputPath)
+	}
+	fonts := c.newFontSet()
+	root, err := html.Parse(strings.NewReader(htmlStr))
+	if err != nil {
+		return nil, fmt.Errorf("parsing HTML: %w", err)
+	}
+	flattenCode(root)
+	fonts.apply(root)
+	var buf bytes.Buffer
+	if err := html.Render(&buf, root); err != nil {
+		return nil, fmt.Errorf("rendering HTML: %w", err)
+	}
+	htmlStr = buf.String()
+	result, err := folioHTML.ConvertFull(htmlStr, &folioHTML.Options{BasePath: basePath})
+	if err != nil {
+		return nil, fmt.Errorf("HTML to PDF: %w", err)
+	}
+
+	doc, margins := c.newDocument(result)
+	c.setInfo(doc, result.Metadata)
+	var headings []pageHeading
+	if c.pdfOpts.ContentsPage || hasInternalLinks(htmlStr) {
+		headings, _ = layoutHeadings(result, c.pageSize, margins)
+	}
+	pf := fonts.pageFonts()
+	front := c.addFrontPages(doc, contentsEntries(headings, c.contentsDepth(), 0), pf, margins)
 	for _, e := range result.Elements {
 		doc.Add(e)
 	}
-	if result.Metadata.Title != "" {
-		doc.Info.Title = result.Metadata.Title
-	}
+	c.decoratePages(doc, documentStatus(htmlStr), pf, margins,
+		newRunningValues(result.Metadata.Title, inputPath, doc.Info.CreationDate))
 	doc.SetAutoBookmarks(true)
+	addHeadingDests(doc, htmlStr, headings, front)
 	return doc, nil
 }
+
+// addFrontPages adds the configured cover and contents pages to doc, whose
+// document information must already be set, and returns their number.
+// entries are the headings of the body, with their body page indexes.
+func (c *Converter) addFrontPages(doc *folio.Document, entries []contentsEntry, fonts pageFonts,
+	margins layout.Margins) int {
+	front := 0
+	if c.pdfOpts.Cover.Enabled {
+		addCoverPage(doc, c.pdfOpts.Cover, c.coverLogo, fonts, c.pageSize, margins)
+		front++
+	}
+	if c.pdfOpts.ContentsPage {
+		front += contentsPageCount(len(entries), c.pageSize, margins)
+		addContentsPages(doc, entries, front, fonts, c.pageSize, margins)
+	}
+	return front
+}
+
+// decoratePages draws the running header and footer and the watermark of
+// a document with the given front matter status on the pages of doc.
+func (c *Converter) decoratePages(doc *folio.Document, status string, fonts pageFonts,
+	margins layout.Margins, values runningValues) {
+	header, footer := runningText(c.pdfOpts.Header, c.pdfOpts.Footer, c.firstRunningPage(),
+		c.pdfOpts.HeaderFooterSkipFirst, fonts.regular, c.pageSize, margins, values)
+	// The footer decorator runs last, so a watermark drawn over the content
+	// covers the header and footer too.
+	if mark := c.watermarkDecorator(status, fonts.bold); mark != nil {
+		if footer == nil {
+			footer = mark
+		} else {
+			text := footer
+			footer = func(ctx folio.PageContext, page *folio.Page) {
+				text(ctx, page)
+				mark(ctx, page)
+			}
+		}
+	}
+	if header != nil {
+		doc.SetHeader(header)
+	}
+	if footer != nil {
+		doc.SetFooter(footer)
+	}
+}
+
+// contentsDepth returns the deepest heading level of the contents page.
+func (c *Converter) contentsDepth() int {
+	return cmp.Or(c.pdfOpts.ContentsDepth, DefaultContentsDepth)
+}
+
+// firstRunningPage returns the index of the first page that may carry a
+// running header and footer: the one after the cover page, if any.
+func (c *Converter) firstRunningPage() int {
+	if c.pdfOpts.Cover.Enabled {
+		return 1
+	}
+	return 0
+}
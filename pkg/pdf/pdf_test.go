@@ -3,10 +3,22 @@ package pdf_test
 import (
 	"bytes"
 	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"math"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/carlos7ags/folio/reader"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/goregular"
 
 	"github.com/sgaunet/mdtohtml/pkg/converter"
 	"github.com/sgaunet/mdtohtml/pkg/pdf"
@@ -50,6 +62,109 @@ func TestConvert_TitleEmbeddedInMetadata(t *testing.T) {
 	}
 }
 
+func TestConvert_FrontMatterEmbeddedInMetadata(t *testing.T) {
+	in := "---\ntitle: Front Title\nauthor: Front Author\ndescription: Front Subject\nkeywords: alpha, beta\n---\n# Heading\n"
+	out, err := newConv(t).Convert([]byte(in))
+	if err != nil {
+		t.Fatalf("Convert: %v", err)
+	}
+	for _, want := range []string{"Front Title", "Front Author", "Front Subject", "alpha, beta"} {
+		if !bytes.Contains(out, []byte(want)) {
+			t.Errorf("expected %q to appear in PDF Info dictionary", want)
+		}
+	}
+}
+
+func TestConvert_HighlightedCode(t *testing.T) {
+	opts := converter.DefaultOptions()
+	opts.HighlightStyle = "github"
+	opts.HighlightCSSClasses = true // ignored: PDFs always use inline styles
+	opts.HighlightLineNumbers = true
+	c, err := pdf.New(opts, pdf.DefaultOptions())
+	if err != nil {
+		t.Fatalf("pdf.New: %v", err)
+	}
+	out, err := c.Convert([]byte("# Code\n\n```go\nfunc main() {\n\treturn\n}\n```\n"))
+	if err != nil {
+		t.Fatalf("Convert: %v", err)
+	}
+	r, err := reader.Parse(out)
+	if err != nil {
+		t.Fatalf("reader.Parse: %v", err)
+	}
+	page, err := r.Page(0)
+	if err != nil {
+		t.Fatalf("Page(0): %v", err)
+	}
+	content, err := page.ContentStream()
+	if err != nil {
+		t.Fatalf("ContentStream: %v", err)
+	}
+	// #cf222e, the colour of keywords in the github style.
+	if !bytes.Contains(content, []byte("0.811765 0.133333 0.180392 rg")) {
+		t.Errorf("keywords should be drawn in the colour of the style, got %q", content)
+	}
+	// The indented line starts right of the others, after its line number.
+	x := map[string]float64{}
+	for _, m := range regexp.MustCompile(`([\d.]+) [\d.]+ Td\s*\((func|return|1|2)\) Tj`).FindAllSubmatch(content, -1) {
+		x[string(m[2])], _ = strconv.ParseFloat(string(m[1]), 64)
+	}
+	if len(x) != 4 || x["1"] != x["2"] || x["1"] >= x["func"] || x["func"] >= x["return"] {
+		t.Errorf("expected line numbers before the code and indentation, got x positions %v", x)
+	}
+}
+
+func TestConvert_TOCLinksToHeadings(t *testing.T) {
+	opts := converter.DefaultOptions()
+	opts.TOC = converter.TOCOptions{Enabled: true}
+	c, err := pdf.New(opts, pdf.DefaultOptions())
+	if err != nil {
+		t.Fatalf("pdf.New: %v", err)
+	}
+	out, err := c.Convert([]byte("# Manual\n\n## Install\n\ntext\n\n## Usage\n\ntext\n"))
+	if err != nil {
+		t.Fatalf("Convert: %v", err)
+	}
+	// Every heading gets a named destination and the TOC entries link to
+	// pages rather than to "#id" URIs.
+	for _, want := range []string{"/manual [", "/install [", "/usage ["} {
+		if !bytes.Contains(out, []byte(want)) {
+			t.Errorf("expected named destination %q in PDF", want)
+		}
+	}
+	if n := bytes.Count(out, []byte("/Dest [")); n < 3 {
+		t.Errorf("expected at least 3 internal link annotations, got %d", n)
+	}
+	if bytes.Contains(out, []byte("/URI (#")) {
+		t.Error("TOC links should not be URI actions")
+	}
+}
+
+func TestConvert_Alerts(t *testing.T) {
+	out, err := newConv(t).Convert([]byte("> [!WARNING]\n> Mind the gap.\n"))
+	if err != nil {
+		t.Fatalf("Convert: %v", err)
+	}
+	r, err := reader.Parse(out)
+	if err != nil {
+		t.Fatalf("reader.Parse: %v", err)
+	}
+	page, err := r.Page(0)
+	if err != nil {
+		t.Fatalf("Page(0): %v", err)
+	}
+	text, err := page.ExtractText()
+	if err != nil {
+		t.Fatalf("ExtractText: %v", err)
+	}
+	if !strings.Contains(text, "Warning") || !strings.Contains(text, "Mind the gap.") {
+		t.Errorf("expected alert title and body in page text, got %q", text)
+	}
+	if strings.Contains(text, "[!WARNING]") {
+		t.Errorf("alert marker should not be rendered, got %q", text)
+	}
+}
+
 func TestConvertFile_WritesPDF(t *testing.T) {
 	dir := t.TempDir()
 	in := filepath.Join(dir, "doc.md")
@@ -90,15 +205,121 @@ func TestConvertFile_PageBreakFixture(t *testing.T) {
 	}
 }
 
+func TestConvert_PageBreakMarkers(t *testing.T) {
+	cases := []struct {
+		name     string
+		beforeH1 bool
+		pages    int
+	}{
+		{"markers", false, 3},
+		{"markers and H1 breaks", true, 4},
+	}
+	md := "# One\n\n\\pagebreak\n\n## Sub\n\n<!-- pagebreak -->\n\n# Two\n\ntext\n\n# Three\n"
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			opts := converter.DefaultOptions()
+			opts.SafeMode = true
+			opts.PageBreakBeforeH1 = c.beforeH1
+			conv, err := pdf.New(opts, pdf.DefaultOptions())
+			if err != nil {
+				t.Fatalf("pdf.New: %v", err)
+			}
+			out, err := conv.Convert([]byte(md))
+			if err != nil {
+				t.Fatalf("Convert: %v", err)
+			}
+			r, err := reader.Parse(out)
+			if err != nil {
+				t.Fatalf("reader.Parse: %v", err)
+			}
+			if got := r.PageCount(); got != c.pages {
+				t.Errorf("expected %d pages, got %d", c.pages, got)
+			}
+		})
+	}
+}
+
 func TestNew_RejectsUnknownPageSize(t *testing.T) {
-	_, err := pdf.New(converter.DefaultOptions(), pdf.Options{PageSize: "Bogus"})
-	if !errors.Is(err, pdf.ErrUnknownPageSize) {
-		t.Fatalf("expected ErrUnknownPageSize, got %v", err)
+	for _, ps := range []string{"Bogus", "A11", "D4", "A-1"} {
+		t.Run(ps, func(t *testing.T) {
+			_, err := pdf.New(converter.DefaultOptions(), pdf.Options{PageSize: ps})
+			if !errors.Is(err, pdf.ErrUnknownPageSize) {
+				t.Fatalf("expected ErrUnknownPageSize, got %v", err)
+			}
+			// The message lists the valid names.
+			if !strings.Contains(err.Error(), "A0-A10") || !strings.Contains(err.Error(), "Letter") {
+				t.Errorf("error %q does not list the valid page sizes", err)
+			}
+		})
+	}
+}
+
+func TestNew_RejectsInvalidPageDimensions(t *testing.T) {
+	for _, ps := range []string{"6inx", "0x5in", "-1inx2in", "3ftx2in", "6inxabc"} {
+		t.Run(ps, func(t *testing.T) {
+			_, err := pdf.New(converter.DefaultOptions(), pdf.Options{PageSize: ps})
+			if !errors.Is(err, pdf.ErrInvalidPageSize) {
+				t.Fatalf("expected ErrInvalidPageSize, got %v", err)
+			}
+		})
 	}
 }
 
+func TestNew_PageSizeDimensions(t *testing.T) {
+	cases := []struct {
+		pageSize              string
+		wantWidth, wantHeight float64
+	}{
+		{"A4", 595.28, 841.89},
+		{"A6", 297.64, 419.53},
+		{"B5", 498.9, 708.66},
+		{"C5", 459.21, 649.13},
+		{"Executive", 522, 756},
+		{"Ledger", 1224, 792},
+		{"6inx9in", 432, 648},
+		{"200x100", 200, 100},
+	}
+	for _, c := range cases {
+		t.Run(c.pageSize, func(t *testing.T) {
+			w, h := pageDimensions(t, pdf.Options{PageSize: c.pageSize})
+			if !approxEqual(w, c.wantWidth) || !approxEqual(h, c.wantHeight) {
+				t.Errorf("page is %vx%v, want %vx%v", w, h, c.wantWidth, c.wantHeight)
+			}
+		})
+	}
+}
+
+// approxEqual reports whether two lengths in points agree to 0.01pt.
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) < 0.01
+}
+
+// pageDimensions renders a short document with pdfOpts and returns the size
+// of its first page.
+func pageDimensions(t *testing.T, pdfOpts pdf.Options) (float64, float64) {
+	t.Helper()
+	conv, err := pdf.New(converter.DefaultOptions(), pdfOpts)
+	if err != nil {
+		t.Fatalf("pdf.New: %v", err)
+	}
+	out, err := conv.Convert([]byte("# Page\n\nbody\n"))
+	if err != nil {
+		t.Fatalf("Convert: %v", err)
+	}
+	r, err := reader.Parse(out)
+	if err != nil {
+		t.Fatalf("reader.Parse: %v", err)
+	}
+	page, err := r.Page(0)
+	if err != nil {
+		t.Fatalf("Page(0): %v", err)
+	}
+	return page.Width, page.Height
+}
+
 func TestNew_AcceptsKnownPageSizes(t *testing.T) {
-	for _, ps := range []string{"", "A4", "a4", "Letter", "Legal", "A3", "A5", "Tabloid"} {
+	sizes := append([]string{"", "a4", "b5", "LETTER", "210mmx148mm", "6inx9in", "6in x 9in", "100X200"}, pdf.PageSizes()...)
+	for _, ps := range sizes {
 		t.Run(ps, func(t *testing.T) {
 			if _, err := pdf.New(converter.DefaultOptions(), pdf.Options{PageSize: ps}); err != nil {
 				t.Fatalf("page size %q rejected: %v", ps, err)
@@ -173,3 +394,789 @@ func TestConvert_HonoursCustomMargin(t *testing.T) {
 		t.Fatal("output is not a PDF")
 	}
 }
+
+func TestParseMargins(t *testing.T) {
+	d := pdf.DefaultMargin
+	cases := []struct {
+		in   string
+		want pdf.Margins
+	}{
+		{"", pdf.Margins{Top: d, Right: d, Bottom: d, Left: d}},
+		{"1in", pdf.Margins{Top: 72, Right: 72, Bottom: 72, Left: 72}},
+		{"1in 0.5in", pdf.Margins{Top: 72, Right: 36, Bottom: 72, Left: 36}},
+		{"10 20 30", pdf.Margins{Top: 10, Right: 20, Bottom: 30, Left: 20}},
+		{"10 20 30 40", pdf.Margins{Top: 10, Right: 20, Bottom: 30, Left: 40}},
+		{"  1in   2.54cm\t0 ", pdf.Margins{Top: 72, Right: 72, Bottom: 0, Left: 72}},
+	}
+	for _, c := range cases {
+		t.Run(c.in, func(t *testing.T) {
+			got, err := pdf.ParseMargins(c.in)
+			if err != nil {
+				t.Fatalf("ParseMargins(%q) err: %v", c.in, err)
+			}
+			sides := [][2]float64{
+				{got.Top, c.want.Top}, {got.Right, c.want.Right},
+				{got.Bottom, c.want.Bottom}, {got.Left, c.want.Left},
+			}
+			for _, s := range sides {
+				if diff := s[0] - s[1]; diff < -0.001 || diff > 0.001 {
+					t.Fatalf("ParseMargins(%q) = %+v, want %+v", c.in, got, c.want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseMargins_Errors(t *testing.T) {
+	for _, in := range []string{"1in abc", "1 2 3 4 5", "1in -5"} {
+		t.Run(in, func(t *testing.T) {
+			if _, err := pdf.ParseMargins(in); !errors.Is(err, pdf.ErrInvalidMargin) {
+				t.Fatalf("ParseMargins(%q) expected ErrInvalidMargin, got %v", in, err)
+			}
+		})
+	}
+}
+
+func TestNew_Orientation(t *testing.T) {
+	cases := []struct {
+		pageSize, orientation string
+		wantWidth, wantHeight float64
+	}{
+		{"A4", "", 595.28, 841.89},
+		{"A4", "portrait", 595.28, 841.89},
+		{"A4", "landscape", 841.89, 595.28},
+		{"Letter", "Landscape", 792, 612},
+		{"Ledger", "", 1224, 792},
+		{"Ledger", "portrait", 792, 1224},
+		{"200x100", "", 200, 100},
+		{"200x100", "landscape", 200, 100},
+	}
+	for _, c := range cases {
+		t.Run(c.pageSize+"/"+c.orientation, func(t *testing.T) {
+			w, h := pageDimensions(t, pdf.Options{PageSize: c.pageSize, Orientation: c.orientation})
+			if w != c.wantWidth || h != c.wantHeight {
+				t.Errorf("page is %vx%v, want %vx%v", w, h, c.wantWidth, c.wantHeight)
+			}
+		})
+	}
+}
+
+func TestNew_RejectsUnknownOrientation(t *testing.T) {
+	_, err := pdf.New(converter.DefaultOptions(), pdf.Options{Orientation: "sideways"})
+	if !errors.Is(err, pdf.ErrUnknownOrientation) {
+		t.Fatalf("expected ErrUnknownOrientation, got %v", err)
+	}
+}
+
+func TestParseRunningText(t *testing.T) {
+	cases := []struct {
+		in   string
+		want pdf.RunningText
+	}{
+		{"", pdf.RunningText{}},
+		{"Page {page} of {pages}", pdf.RunningText{Center: "Page {page} of {pages}"}},
+		{"{title}|{date}", pdf.RunningText{Left: "{title}", Right: "{date}"}},
+		{"{filename}|{title}|{page}", pdf.RunningText{Left: "{filename}", Center: "{title}", Right: "{page}"}},
+		{"{title}||{page}/{pages}", pdf.RunningText{Left: "{title}", Right: "{page}/{pages}"}},
+		{"no placeholders", pdf.RunningText{Center: "no placeholders"}},
+	}
+	for _, c := range cases {
+		t.Run(c.in, func(t *testing.T) {
+			got, err := pdf.ParseRunningText(c.in)
+			if err != nil {
+				t.Fatalf("ParseRunningText(%q) err: %v", c.in, err)
+			}
+			if got != c.want {
+				t.Errorf("ParseRunningText(%q) = %+v, want %+v", c.in, got, c.want)
+			}
+		})
+	}
+}
+
+func TestParseRunningText_Errors(t *testing.T) {
+	for _, in := range []string{"a|b|c|d", "{author}", "Page {Page}", "{}"} {
+		t.Run(in, func(t *testing.T) {
+			if _, err := pdf.ParseRunningText(in); !errors.Is(err, pdf.ErrInvalidTemplate) {
+				t.Fatalf("ParseRunningText(%q) expected ErrInvalidTemplate, got %v", in, err)
+			}
+		})
+	}
+}
+
+func TestConvertFile_HeaderAndFooter(t *testing.T) {
+	dir := t.TempDir()
+	in := filepath.Join(dir, "report.md")
+	md := "---\ntitle: Quarterly Report\n---\n# One\n\n" + strings.Repeat("Lorem ipsum dolor sit amet.\n\n", 80)
+	if err := os.WriteFile(in, []byte(md), 0o600); err != nil {
+		t.Fatalf("write input: %v", err)
+	}
+	cases := []struct {
+		name      string
+		skipFirst bool
+	}{
+		{"every page", false},
+		{"skip first", true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			opts := pdf.DefaultOptions()
+			opts.Header, _ = pdf.ParseRunningText("{title}||{filename}")
+			opts.Footer, _ = pdf.ParseRunningText("Page {page} of {pages}")
+			opts.HeaderFooterSkipFirst = c.skipFirst
+			opts.CreationDate = time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+			conv, err := pdf.New(converter.DefaultOptions(), opts)
+			if err != nil {
+				t.Fatalf("pdf.New: %v", err)
+			}
+			out := filepath.Join(dir, "report.pdf")
+			if err := conv.ConvertFile(in, out); err != nil {
+				t.Fatalf("ConvertFile: %v", err)
+			}
+			data, err := os.ReadFile(out)
+			if err != nil {
+				t.Fatalf("read output: %v", err)
+			}
+			r, err := reader.Parse(data)
+			if err != nil {
+				t.Fatalf("reader.Parse: %v", err)
+			}
+			pages := r.PageCount()
+			if pages < 2 {
+				t.Fatalf("expected several pages, got %d", pages)
+			}
+			for i := range pages {
+				page, err := r.Page(i)
+				if err != nil {
+					t.Fatalf("Page(%d): %v", i, err)
+				}
+				text, err := page.ExtractText()
+				if err != nil {
+					t.Fatalf("ExtractText: %v", err)
+				}
+				footer := fmt.Sprintf("Page %d of %d", i+1, pages)
+				want := !c.skipFirst || i > 0
+				for _, s := range []string{footer, "Quarterly Report", "report.md"} {
+					if strings.Contains(text, s) != want {
+						t.Errorf("page %d: contains %q = %v, want %v", i+1, s, !want, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestConvert_MetadataOptions(t *testing.T) {
+	in := "---\ntitle: Front Title\nauthor: Front Author\ndescription: Front Subject\nkeywords: alpha, beta\n---\n# Heading\n"
+	cases := []struct {
+		name string
+		opts pdf.Options
+		want []string
+	}{
+		{
+			name: "front matter fallback",
+			want: []string{"/Author (Front Author)", "/Subject (Front Subject)", "/Keywords (alpha, beta)",
+				"/Creator (" + pdf.DefaultCreator + ")"},
+		},
+		{
+			name: "options override front matter",
+			opts: pdf.Options{Author: "Opt Author", Subject: "Opt Subject", Keywords: "gamma", Creator: "ci", Producer: "pipeline"},
+			want: []string{"/Author (Opt Author)", "/Subject (Opt Subject)", "/Keywords (gamma)",
+				"/Creator (ci)", "/Producer (pipeline)"},
+		},
+		{
+			name: "creation date",
+			opts: pdf.Options{CreationDate: time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)},
+			want: []string{"/CreationDate (D:20240301123000+00'00')"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			conv, err := pdf.New(converter.DefaultOptions(), c.opts)
+			if err != nil {
+				t.Fatalf("pdf.New: %v", err)
+			}
+			out, err := conv.Convert([]byte(in))
+			if err != nil {
+				t.Fatalf("Convert: %v", err)
+			}
+			for _, want := range c.want {
+				if !bytes.Contains(out, []byte(want)) {
+					t.Errorf("expected %q in PDF Info dictionary", want)
+				}
+			}
+		})
+	}
+}
+
+func TestConvert_ReproducibleWithCreationDate(t *testing.T) {
+	opts := pdf.DefaultOptions()
+	opts.CreationDate = time.Unix(1700000000, 0).UTC()
+	opts.Footer = pdf.RunningText{Center: "{date} {page}/{pages}"}
+	conv, err := pdf.New(converter.DefaultOptions(), opts)
+	if err != nil {
+		t.Fatalf("pdf.New: %v", err)
+	}
+	in := []byte("# Same\n\nInput with `code` and a [link](#same).\n")
+	first, err := conv.Convert(in)
+	if err != nil {
+		t.Fatalf("Convert: %v", err)
+	}
+	second, err := conv.Convert(in)
+	if err != nil {
+		t.Fatalf("Convert: %v", err)
+	}
+	if !bytes.Equal(first, second) {
+		t.Fatal("identical inputs produced different PDFs")
+	}
+}
+
+func TestSourceDateEpoch(t *testing.T) {
+	cases := []struct {
+		in   string
+		want time.Time
+	}{
+		{"", time.Time{}},
+		{"0", time.Unix(0, 0).UTC()},
+		{" 1700000000 ", time.Date(2023, 11, 14, 22, 13, 20, 0, time.UTC)},
+	}
+	for _, c := range cases {
+		t.Run(c.in, func(t *testing.T) {
+			got, err := pdf.SourceDateEpoch(c.in)
+			if err != nil {
+				t.Fatalf("SourceDateEpoch(%q) err: %v", c.in, err)
+			}
+			if !got.Equal(c.want) || got.Location() != c.want.Location() {
+				t.Errorf("SourceDateEpoch(%q) = %v, want %v", c.in, got, c.want)
+			}
+		})
+	}
+}
+
+func TestSourceDateEpoch_Errors(t *testing.T) {
+	for _, in := range []string{"abc", "-1", "1.5", "2024-01-01"} {
+		t.Run(in, func(t *testing.T) {
+			if _, err := pdf.SourceDateEpoch(in); !errors.Is(err, pdf.ErrInvalidSourceDateEpoch) {
+				t.Fatalf("SourceDateEpoch(%q) expected ErrInvalidSourceDateEpoch, got %v", in, err)
+			}
+		})
+	}
+}
+
+func TestParseSummary(t *testing.T) {
+	dir := filepath.Join("..", "..", "tst", "integration", "fixtures", "book")
+	chapters, err := pdf.ParseSummary(filepath.Join(dir, "SUMMARY.md"))
+	if err != nil {
+		t.Fatalf("ParseSummary: %v", err)
+	}
+	want := []pdf.Chapter{
+		{Title: "Introduction", Path: filepath.Join(dir, "intro.md")},
+		{Title: "Guide", Path: filepath.Join(dir, "guide", "index.md"), Children: []pdf.Chapter{
+			{Title: "Setup", Path: filepath.Join(dir, "guide", "setup.md")},
+		}},
+	}
+	if fmt.Sprint(chapters) != fmt.Sprint(want) {
+		t.Errorf("ParseSummary = %+v, want %+v", chapters, want)
+	}
+}
+
+func TestParseSummary_NoChapters(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "SUMMARY.md")
+	if err := os.WriteFile(path, []byte("# Summary\n\n- [Draft]()\n- [Site](https://example.com)\n"), 0o600); err != nil {
+		t.Fatalf("write summary: %v", err)
+	}
+	if _, err := pdf.ParseSummary(path); !errors.Is(err, pdf.ErrEmptyBook) {
+		t.Fatalf("expected ErrEmptyBook, got %v", err)
+	}
+}
+
+func TestConvertBook(t *testing.T) {
+	dir := filepath.Join("..", "..", "tst", "integration", "fixtures", "book")
+	chapters, err := pdf.ParseSummary(filepath.Join(dir, "SUMMARY.md"))
+	if err != nil {
+		t.Fatalf("ParseSummary: %v", err)
+	}
+	out := filepath.Join(t.TempDir(), "book.pdf")
+	if err := newConv(t).ConvertBook(chapters, out); err != nil {
+		t.Fatalf("ConvertBook: %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+	r, err := reader.Parse(data)
+	if err != nil {
+		t.Fatalf("reader.Parse: %v", err)
+	}
+	// Each of the three chapters starts on a new page.
+	if got := r.PageCount(); got < 3 {
+		t.Errorf("expected at least 3 pages, got %d", got)
+	}
+	for i, want := range []string{"Introduction", "Guide", "Setup"} {
+		page, err := r.Page(i)
+		if err != nil {
+			t.Fatalf("Page(%d): %v", i, err)
+		}
+		text, err := page.ExtractText()
+		if err != nil {
+			t.Fatalf("ExtractText: %v", err)
+		}
+		if !strings.HasPrefix(strings.TrimSpace(text), want) {
+			t.Errorf("page %d should open chapter %q, got %q", i+1, want, text)
+		}
+	}
+	for _, want := range []string{
+		// Cross-file links point at the chapter's first heading or the
+		// heading of the fragment; fragment-only links get the prefix too.
+		"#chapter-2-guide", "#chapter-3-setup", "#chapter-3-install", "#chapter-1-audience",
+		// Bookmarks of the chapters and of their headings.
+		"/Title (Guide)", "/Title (Setup)", "/Title (Install)", "/Title (Audience)",
+		// The image of guide/setup.md resolves against guide/.
+		"/Subtype /Image",
+	} {
+		if !bytes.Contains(data, []byte(want)) {
+			t.Errorf("expected %q in the book", want)
+		}
+	}
+}
+
+func TestConvertFiles_DuplicateHeadingIDs(t *testing.T) {
+	dir := t.TempDir()
+	var paths []string
+	for _, name := range []string{"a.md", "b.md"} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("# Overview\n\nSee [usage](#usage).\n\n## Usage\n"), 0o600); err != nil {
+			t.Fatalf("write chapter: %v", err)
+		}
+		paths = append(paths, path)
+	}
+	out := filepath.Join(dir, "book.pdf")
+	if err := newConv(t).ConvertFiles(paths, out); err != nil {
+		t.Fatalf("ConvertFiles: %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+	for _, want := range []string{"/chapter-1-usage", "/chapter-2-usage", "#chapter-1-usage", "#chapter-2-usage"} {
+		if !bytes.Contains(data, []byte(want)) {
+			t.Errorf("expected %q in the book", want)
+		}
+	}
+}
+
+func TestConvertBook_Errors(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "book.pdf")
+	if err := newConv(t).ConvertBook(nil, out); !errors.Is(err, pdf.ErrEmptyBook) {
+		t.Errorf("expected ErrEmptyBook, got %v", err)
+	}
+	missing := []pdf.Chapter{{Path: filepath.Join(t.TempDir(), "missing.md")}}
+	if err := newConv(t).ConvertBook(missing, out); err == nil || !strings.Contains(err.Error(), "missing.md") {
+		t.Errorf("expected an error naming the missing chapter, got %v", err)
+	}
+}
+
+func TestConvert_CoverAndContentsPages(t *testing.T) {
+	opts := pdf.DefaultOptions()
+	opts.Cover = pdf.Cover{Enabled: true, Subtitle: "Third quarter"}
+	opts.ContentsPage = true
+	opts.CreationDate = time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	opts.Footer, _ = pdf.ParseRunningText("Page {page}")
+	conv, err := pdf.New(converter.DefaultOptions(), opts)
+	if err != nil {
+		t.Fatalf("pdf.New: %v", err)
+	}
+	md := "---\ntitle: Quarterly Report\nauthor: Jane Roe\n---\n# Summary\n\n" +
+		strings.Repeat("Lorem ipsum dolor sit amet.\n\n", 80) + "## Findings\n\ntext\n\n#### Detail\n\ntext\n"
+	out, err := conv.Convert([]byte(md))
+	if err != nil {
+		t.Fatalf("Convert: %v", err)
+	}
+	r, err := reader.Parse(out)
+	if err != nil {
+		t.Fatalf("reader.Parse: %v", err)
+	}
+	pageText := func(i int) string {
+		t.Helper()
+		page, err := r.Page(i)
+		if err != nil {
+			t.Fatalf("Page(%d): %v", i, err)
+		}
+		text, err := page.ExtractText()
+		if err != nil {
+			t.Fatalf("ExtractText: %v", err)
+		}
+		return text
+	}
+
+	cover := pageText(0)
+	for _, want := range []string{"Quarterly Report", "Third quarter", "Jane Roe", "March 1, 2024"} {
+		if !strings.Contains(cover, want) {
+			t.Errorf("cover page should contain %q, got %q", want, cover)
+		}
+	}
+	if strings.Contains(cover, "Page 1") {
+		t.Errorf("cover page should have no footer, got %q", cover)
+	}
+
+	contents := pageText(1)
+	if strings.Contains(contents, "Detail") {
+		t.Errorf("contents page should stop at level 3, got %q", contents)
+	}
+	// Every entry shows the page its heading is laid out on.
+	for _, heading := range []string{"Summary", "Findings"} {
+		m := regexp.MustCompile(heading + `[ .]*\.[ .]*(\d+)`).FindStringSubmatch(contents)
+		if m == nil {
+			t.Fatalf("contents page should list %q with leaders and a page number, got %q", heading, contents)
+		}
+		n, _ := strconv.Atoi(m[1])
+		if n < 3 || n > r.PageCount() {
+			t.Fatalf("%q listed on page %d of %d", heading, n, r.PageCount())
+		}
+		if text := pageText(n - 1); !strings.Contains(text, heading) {
+			t.Errorf("page %d should contain %q, got %q", n, heading, text)
+		}
+	}
+}
+
+func TestNew_RejectsInvalidCoverAndContents(t *testing.T) {
+	cases := []struct {
+		name string
+		opts func(*pdf.Options)
+		want error
+	}{
+		{"logo format", func(o *pdf.Options) { o.Cover = pdf.Cover{Enabled: true, Logo: "logo.gif"} }, pdf.ErrInvalidCoverLogo},
+		{"missing logo", func(o *pdf.Options) {
+			o.Cover = pdf.Cover{Enabled: true, Logo: filepath.Join(t.TempDir(), "logo.png")}
+		}, pdf.ErrInvalidCoverLogo},
+		{"contents depth", func(o *pdf.Options) { o.ContentsDepth = 7 }, pdf.ErrInvalidContentsDepth},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			opts := pdf.DefaultOptions()
+			c.opts(&opts)
+			if _, err := pdf.New(converter.DefaultOptions(), opts); !errors.Is(err, c.want) {
+				t.Errorf("expected %v, got %v", c.want, err)
+			}
+		})
+	}
+}
+
+func TestConvertBook_CoverAndContentsPages(t *testing.T) {
+	dir := filepath.Join("..", "..", "tst", "integration", "fixtures", "book")
+	chapters, err := pdf.ParseSummary(filepath.Join(dir, "SUMMARY.md"))
+	if err != nil {
+		t.Fatalf("ParseSummary: %v", err)
+	}
+	opts := pdf.DefaultOptions()
+	opts.Cover = pdf.Cover{Enabled: true, Title: "User Manual"}
+	opts.ContentsPage = true
+	conv, err := pdf.New(converter.DefaultOptions(), opts)
+	if err != nil {
+		t.Fatalf("pdf.New: %v", err)
+	}
+	out := filepath.Join(t.TempDir(), "book.pdf")
+	if err := conv.ConvertBook(chapters, out); err != nil {
+		t.Fatalf("ConvertBook: %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+	r, err := reader.Parse(data)
+	if err != nil {
+		t.Fatalf("reader.Parse: %v", err)
+	}
+	var texts []string
+	for i := range r.PageCount() {
+		page, err := r.Page(i)
+		if err != nil {
+			t.Fatalf("Page(%d): %v", i, err)
+		}
+		text, err := page.ExtractText()
+		if err != nil {
+			t.Fatalf("ExtractText: %v", err)
+		}
+		texts = append(texts, text)
+	}
+	if len(texts) < 5 || !strings.Contains(texts[0], "User Manual") || !strings.HasPrefix(texts[1], "Contents") {
+		t.Fatalf("expected a cover and a contents page before the chapters, got %q", texts)
+	}
+	// The chapters follow the front pages, as listed on the contents page.
+	for i, want := range []string{"Introduction", "Guide", "Setup"} {
+		if !strings.HasPrefix(strings.TrimSpace(texts[i+2]), want) {
+			t.Errorf("page %d should open chapter %q, got %q", i+3, want, texts[i+2])
+		}
+		if !regexp.MustCompile(want + `[ .]*\.[ .]*` + strconv.Itoa(i+3)).MatchString(texts[1]) {
+			t.Errorf("contents page should list %q on page %d, got %q", want, i+3, texts[1])
+		}
+	}
+}
+
+func TestParseFont(t *testing.T) {
+	cases := []struct {
+		in   string
+		want pdf.Font
+	}{
+		{"Inter.ttf", pdf.Font{Regular: "Inter.ttf"}},
+		{"Inter.ttf, Inter-Bold.ttf", pdf.Font{Regular: "Inter.ttf", Bold: "Inter-Bold.ttf"}},
+		{"a.otf,b.otf,c.otf,d.otf", pdf.Font{Regular: "a.otf", Bold: "b.otf", Italic: "c.otf", BoldItalic: "d.otf"}},
+		{"a.ttf,,c.ttf", pdf.Font{Regular: "a.ttf", Italic: "c.ttf"}},
+	}
+	for _, c := range cases {
+		got, err := pdf.ParseFont(c.in)
+		if err != nil {
+			t.Errorf("ParseFont(%q): %v", c.in, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("ParseFont(%q) = %+v, want %+v", c.in, got, c.want)
+		}
+	}
+}
+
+func TestParseFont_Errors(t *testing.T) {
+	for _, in := range []string{"", " ", ",Bold.ttf", "a,b,c,d,e"} {
+		if _, err := pdf.ParseFont(in); !errors.Is(err, pdf.ErrInvalidFont) {
+			t.Errorf("ParseFont(%q): expected ErrInvalidFont, got %v", in, err)
+		}
+	}
+}
+
+// writeFont writes the font data to a file in dir and returns its path.
+func writeFont(t *testing.T, dir, name string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestNew_RejectsInvalidFonts(t *testing.T) {
+	dir := t.TempDir()
+	notFont := writeFont(t, dir, "notes.ttf", []byte("not a font"))
+	regular := writeFont(t, dir, "GoRegular.ttf", goregular.TTF)
+	cases := map[string]pdf.Fonts{
+		"missing body font": {Body: []pdf.Font{{Regular: filepath.Join(dir, "missing.ttf")}}},
+		"missing bold font": {Body: []pdf.Font{{Regular: regular, Bold: filepath.Join(dir, "missing.ttf")}}},
+		"not a font":        {Mono: []pdf.Font{{Regular: notFont}}},
+	}
+	for name, fonts := range cases {
+		t.Run(name, func(t *testing.T) {
+			opts := pdf.DefaultOptions()
+			opts.Fonts = fonts
+			if _, err := pdf.New(converter.DefaultOptions(), opts); !errors.Is(err, pdf.ErrInvalidFont) {
+				t.Errorf("expected ErrInvalidFont, got %v", err)
+			}
+		})
+	}
+}
+
+func TestConvert_EmbeddedFonts(t *testing.T) {
+	dir := t.TempDir()
+	opts := pdf.DefaultOptions()
+	opts.Fonts = pdf.Fonts{
+		Body: []pdf.Font{{
+			Regular: writeFont(t, dir, "GoRegular.ttf", goregular.TTF),
+			Bold:    writeFont(t, dir, "GoBold.ttf", gobold.TTF),
+		}},
+		Mono: []pdf.Font{{Regular: writeFont(t, dir, "GoMono.ttf", gomono.TTF)}},
+	}
+	opts.Cover = pdf.Cover{Enabled: true}
+	opts.ContentsPage = true
+	opts.Footer, _ = pdf.ParseRunningText("{title}")
+	conv, err := pdf.New(converter.DefaultOptions(), opts)
+	if err != nil {
+		t.Fatalf("pdf.New: %v", err)
+	}
+	md := "# Привет\n\nТекст **жирный** и `код`.\n\n```\nfmt.Println(\"Ж\")\n```\n"
+	out, err := conv.Convert([]byte(md))
+	if err != nil {
+		t.Fatalf("Convert: %v", err)
+	}
+	for _, want := range []string{"/FontFile2", "+GoRegular", "+Go-Bold", "+GoMono"} {
+		if !bytes.Contains(out, []byte(want)) {
+			t.Errorf("PDF should contain %s", want)
+		}
+	}
+	for _, std := range []string{"/Helvetica", "/Courier"} {
+		if bytes.Contains(out, []byte(std)) {
+			t.Errorf("PDF should not use the standard font %s", std)
+		}
+	}
+
+	r, err := reader.Parse(out)
+	if err != nil {
+		t.Fatalf("reader.Parse: %v", err)
+	}
+	for i, want := range []string{"Привет", "Привет", "Текстжирныйикод"} {
+		page, err := r.Page(i)
+		if err != nil {
+			t.Fatalf("Page(%d): %v", i, err)
+		}
+		text, err := page.ExtractText()
+		if err != nil {
+			t.Fatalf("ExtractText: %v", err)
+		}
+		// Words drawn with embedded fonts are extracted without spaces.
+		if text = strings.Join(strings.Fields(text), ""); !strings.Contains(text, want) {
+			t.Errorf("page %d should contain %q, got %q", i+1, want, text)
+		}
+	}
+}
+
+func TestConvert_FallbackFont(t *testing.T) {
+	const fallback = "/usr/share/fonts/truetype/dejavu/DejaVuSans.ttf"
+	if _, err := os.Stat(fallback); err != nil {
+		t.Skipf("fallback font missing: %v", err)
+	}
+	opts := pdf.DefaultOptions()
+	opts.Fonts.Body = []pdf.Font{
+		{Regular: writeFont(t, t.TempDir(), "GoRegular.ttf", goregular.TTF)},
+		{Regular: fallback},
+	}
+	conv, err := pdf.New(converter.DefaultOptions(), opts)
+	if err != nil {
+		t.Fatalf("pdf.New: %v", err)
+	}
+	// Go Regular has no Georgian glyphs.
+	out, err := conv.Convert([]byte("Latin and ქართული text.\n"))
+	if err != nil {
+		t.Fatalf("Convert: %v", err)
+	}
+	for _, want := range []string{"+GoRegular", "+DejaVuSans"} {
+		if !bytes.Contains(out, []byte(want)) {
+			t.Errorf("PDF should contain %s", want)
+		}
+	}
+	r, err := reader.Parse(out)
+	if err != nil {
+		t.Fatalf("reader.Parse: %v", err)
+	}
+	page, err := r.Page(0)
+	if err != nil {
+		t.Fatalf("Page(0): %v", err)
+	}
+	text, err := page.ExtractText()
+	if err != nil {
+		t.Fatalf("ExtractText: %v", err)
+	}
+	if !strings.Contains(text, "ქართული") {
+		t.Errorf("page should contain the Georgian text, got %q", text)
+	}
+}
+
+func TestConvert_Watermark(t *testing.T) {
+	cases := []struct {
+		name      string
+		watermark pdf.Watermark
+		md        string
+		want      string
+	}{
+		{"text", pdf.Watermark{Text: "CONFIDENTIAL", Angle: 45}, "# Hello\n", "CONFIDENTIAL"},
+		{"text over", pdf.Watermark{Text: "CONFIDENTIAL", Over: true}, "# Hello\n", "CONFIDENTIAL"},
+		{"draft status", pdf.Watermark{}, "---\nstatus: draft\n---\n# Hello\n", "DRAFT"},
+		{"final status", pdf.Watermark{}, "---\nstatus: Final\n---\n# Hello\n", ""},
+		{"text and status", pdf.Watermark{Text: "SECRET"}, "---\nstatus: draft\n---\n# Hello\n", "SECRET"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			opts := pdf.DefaultOptions()
+			opts.Cover = pdf.Cover{Enabled: true}
+			opts.Watermark = c.watermark
+			conv, err := pdf.New(converter.DefaultOptions(), opts)
+			if err != nil {
+				t.Fatalf("pdf.New: %v", err)
+			}
+			out, err := conv.Convert([]byte(c.md))
+			if err != nil {
+				t.Fatalf("Convert: %v", err)
+			}
+			r, err := reader.Parse(out)
+			if err != nil {
+				t.Fatalf("reader.Parse: %v", err)
+			}
+			for i := range r.PageCount() {
+				page, err := r.Page(i)
+				if err != nil {
+					t.Fatalf("Page(%d): %v", i, err)
+				}
+				content, err := page.ContentStream()
+				if err != nil {
+					t.Fatalf("ContentStream: %v", err)
+				}
+				mark := bytes.Index(content, []byte("("+c.want+") Tj"))
+				if c.want == "" {
+					if bytes.Contains(content, []byte(" gs")) {
+						t.Errorf("page %d should have no watermark, got %q", i+1, content)
+					}
+					continue
+				}
+				if mark < 0 || !bytes.Contains(content, []byte(" gs")) {
+					t.Fatalf("page %d should have a translucent %q watermark, got %q", i+1, c.want, content)
+				}
+				if text := bytes.Index(content, []byte("(Hello) Tj")); text >= 0 && (mark > text) != c.watermark.Over {
+					t.Errorf("page %d: watermark at %d and text at %d, over = %v", i+1, mark, text, c.watermark.Over)
+				}
+			}
+		})
+	}
+}
+
+func TestConvert_WatermarkImage(t *testing.T) {
+	logo := filepath.Join(t.TempDir(), "stamp.png")
+	img := image.NewGray(image.Rect(0, 0, 40, 20))
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("png.Encode: %v", err)
+	}
+	if err := os.WriteFile(logo, buf.Bytes(), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	opts := pdf.DefaultOptions()
+	opts.Watermark = pdf.Watermark{Image: logo}
+	conv, err := pdf.New(converter.DefaultOptions(), opts)
+	if err != nil {
+		t.Fatalf("pdf.New: %v", err)
+	}
+	out, err := conv.Convert([]byte("# Hello\n\n" + strings.Repeat("Lorem ipsum dolor sit amet.\n\n", 80)))
+	if err != nil {
+		t.Fatalf("Convert: %v", err)
+	}
+	r, err := reader.Parse(out)
+	if err != nil {
+		t.Fatalf("reader.Parse: %v", err)
+	}
+	if r.PageCount() < 2 {
+		t.Fatalf("expected several pages, got %d", r.PageCount())
+	}
+	for i := range r.PageCount() {
+		page, err := r.Page(i)
+		if err != nil {
+			t.Fatalf("Page(%d): %v", i, err)
+		}
+		if refs, err := page.ImageRefs(); err != nil || len(refs) != 1 {
+			t.Errorf("page %d should have the watermark image, got %v (%v)", i+1, refs, err)
+		}
+	}
+}
+
+func TestNew_RejectsInvalidWatermark(t *testing.T) {
+	cases := map[string]pdf.Watermark{
+		"opacity":       {Text: "DRAFT", Opacity: 1.5},
+		"font size":     {Text: "DRAFT", FontSize: -1},
+		"color name":    {Text: "DRAFT", Color: "red"},
+		"color digits":  {Text: "DRAFT", Color: "#12345"},
+		"image format":  {Image: "stamp.gif"},
+		"missing image": {Image: filepath.Join(t.TempDir(), "stamp.png")},
+	}
+	for name, watermark := range cases {
+		t.Run(name, func(t *testing.T) {
+			opts := pdf.DefaultOptions()
+			opts.Watermark = watermark
+			if _, err := pdf.New(converter.DefaultOptions(), opts); !errors.Is(err, pdf.ErrInvalidWatermark) {
+				t.Errorf("expected ErrInvalidWatermark, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package pdf
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// ParseSummary reads a book outline in the style of mdBook's SUMMARY.md:
+// the links of the document, in order, name the chapter files, and links in
+// nested list items become child chapters of the item above them. The link
+// text is the chapter title. Links without a destination are draft
+// chapters, which are left out while their children move up a level. Paths
+// are resolved against the directory of the outline; links with a URL
+// scheme and absolute paths are ignored.
+func ParseSummary(path string) ([]Chapter, error) {
+	source, err := readInput(path)
+	if err != nil {
+		return nil, err
+	}
+	doc := goldmark.New().Parser().Parse(text.NewReader(source))
+	chapters := summaryChapters(doc, source, filepath.Dir(path))
+	if len(chapters) == 0 {
+		return nil, fmt.Errorf("%w: no chapter links in '%s'", ErrEmptyBook, path)
+	}
+	return chapters, nil
+}
+
+// summaryChapters returns the chapters linked below n. The first link of a
+// list item is its chapter and the lists nested in the item its children;
+// every link outside of list items is a chapter of its own.
+func summaryChapters(n ast.Node, source []byte, dir string) []Chapter {
+	var chapters []Chapter
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		switch c := c.(type) {
+		case *ast.ListItem:
+			chapters = append(chapters, listItemChapters(c, source, dir)...)
+		case *ast.Link:
+			if ch, ok := summaryChapter(c, source, dir); ok {
+				chapters = append(chapters, ch)
+			}
+		default:
+			chapters = append(chapters, summaryChapters(c, source, dir)...)
+		}
+	}
+	return chapters
+}
+
+// listItemChapters returns the chapter of a list item holding the chapters
+// of its nested lists, or only the nested chapters for a draft chapter.
+func listItemChapters(item *ast.ListItem, source []byte, dir string) []Chapter {
+	var link *ast.Link
+	var children []Chapter
+	for c := item.FirstChild(); c != nil; c = c.NextSibling() {
+		if _, ok := c.(*ast.List); ok {
+			children = append(children, summaryChapters(c, source, dir)...)
+			continue
+		}
+		if link == nil {
+			link = firstLink(c)
+		}
+	}
+	if link == nil {
+		return children
+	}
+	ch, ok := summaryChapter(link, source, dir)
+	if !ok {
+		return children
+	}
+	ch.Children = children
+	return []Chapter{ch}
+}
+
+// summaryChapter returns the chapter link points to. It reports false for
+// draft chapters and links that do not name a local file.
+func summaryChapter(link *ast.Link, source []byte, dir string) (Chapter, bool) {
+	dest := string(link.Destination)
+	if i := strings.IndexAny(dest, "?#"); i >= 0 {
+		dest = dest[:i]
+	}
+	u, err := url.Parse(dest)
+	if err != nil || dest == "" || u.Scheme != "" || u.Host != "" || strings.HasPrefix(u.Path, "/") {
+		return Chapter{}, false
+	}
+	return Chapter{
+		Title: collapseSpace(nodeSource(link, source)),
+		Path:  filepath.Join(dir, filepath.FromSlash(u.Path)),
+	}, true
+}
+
+// firstLink returns the first link below n, or nil.
+func firstLink(n ast.Node) *ast.Link {
+	if link, ok := n.(*ast.Link); ok {
+		return link
+	}
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		if link := firstLink(c); link != nil {
+			return link
+		}
+	}
+	return nil
+}
+
+// nodeSource returns the text of the inline nodes below n.
+func nodeSource(n ast.Node, source []byte) string {
+	var sb strings.Builder
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		if t, ok := c.(*ast.Text); ok {
+			sb.Write(t.Segment.Value(source))
+			if t.SoftLineBreak() {
+				sb.WriteByte(' ')
+			}
+			continue
+		}
+		sb.WriteString(nodeSource(c, source))
+	}
+	return sb.String()
+}
@@ -0,0 +1,243 @@
+package pdf
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	folio "github.com/carlos7ags/folio/document"
+	folioimage "github.com/carlos7ags/folio/image"
+	"golang.org/x/net/html"
+)
+
+// Defaults of the watermark style.
+const (
+	DefaultWatermarkOpacity  = 0.2
+	DefaultWatermarkAngle    = 45.0
+	DefaultWatermarkColor    = "#999999"
+	DefaultWatermarkFontSize = 72.0
+)
+
+// Watermark geometry, as fractions of the page.
+const (
+	watermarkImageMax = 0.6 // of the page width and height
+	watermarkTextMax  = 0.8 // of the line through the center at the angle
+	watermarkCapRatio = 0.35
+)
+
+// finalStatuses are the front matter statuses of documents that are not
+// stamped; see Watermark.
+var finalStatuses = []string{"final", "published", "approved", "released"}
+
+// Watermark is drawn in the middle of every page, the cover and contents
+// pages included. When it has neither Text nor Image, a document whose
+// front matter has a status other than final, published, approved or
+// released, e.g. "status: draft", is stamped with the status in capitals
+// in the style of the watermark.
+type Watermark struct {
+	// Text is drawn in bold with its center at the center of the page.
+	Text string
+
+	// Image is a PNG or JPEG image drawn unrotated at the center of the
+	// page, scaled down to fit 60% of the page width and height.
+	Image string
+
+	// Opacity ranges from 0, transparent, to 1, opaque. Zero defaults to
+	// DefaultWatermarkOpacity.
+	Opacity float64
+
+	// Angle turns the text counterclockwise, in degrees. Zero keeps it
+	// horizontal.
+	Angle float64
+
+	// Color of the text, written #RGB or #RRGGBB. Empty defaults to
+	// DefaultWatermarkColor.
+	Color string
+
+	// FontSize of the text in points, reduced for long texts to fit the
+	// page. Zero defaults to DefaultWatermarkFontSize.
+	FontSize float64
+
+	// Over draws the watermark over the content instead of behind it.
+	Over bool
+}
+
+// IsZero reports whether w draws nothing by itself.
+func (w Watermark) IsZero() bool {
+	return w.Text == "" && w.Image == ""
+}
+
+// loadWatermark validates the style of w and returns its color and its
+// loaded image, or nil.
+func loadWatermark(w Watermark) ([3]float64, *folioimage.Image, error) {
+	if w.Opacity < 0 || w.Opacity > 1 {
+		return [3]float64{}, nil, fmt.Errorf("%w: opacity %g (use 0 to 1)", ErrInvalidWatermark, w.Opacity)
+	}
+	if w.FontSize < 0 {
+		return [3]float64{}, nil, fmt.Errorf("%w: font size %g", ErrInvalidWatermark, w.FontSize)
+	}
+	color, err := parseHexColor(cmp.Or(w.Color, DefaultWatermarkColor))
+	if err != nil {
+		return [3]float64{}, nil, err
+	}
+	var img *folioimage.Image
+	if w.Image != "" {
+		if img, err = loadImage(w.Image, ErrInvalidWatermark); err != nil {
+			return [3]float64{}, nil, err
+		}
+	}
+	return color, img, nil
+}
+
+// parseHexColor parses a color written #RGB or #RRGGBB into RGB
+// components from 0 to 1.
+func parseHexColor(s string) ([3]float64, error) {
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) == 3 { //nolint:mnd // #RGB
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	n, err := strconv.ParseUint(hex, 16, 32)
+	if !strings.HasPrefix(s, "#") || len(hex) != 6 || err != nil {
+		return [3]float64{}, fmt.Errorf("%w: color %q (use #RGB or #RRGGBB)", ErrInvalidWatermark, s)
+	}
+	return [3]float64{
+		float64(n>>16&0xff) / 0xff, //nolint:mnd // red byte
+		float64(n>>8&0xff) / 0xff,  //nolint:mnd // green byte
+		float64(n&0xff) / 0xff,     //nolint:mnd // blue byte
+	}, nil
+}
+
+// watermarkText returns the text drawn for a document with the given
+// front matter status: the configured text, or the status in capitals
+// unless the watermark has an image or the status is final.
+func (c *Converter) watermarkText(status string) string {
+	w := c.pdfOpts.Watermark
+	status = strings.TrimSpace(status)
+	if !w.IsZero() || status == "" {
+		return w.Text
+	}
+	for _, final := range finalStatuses {
+		if strings.EqualFold(status, final) {
+			return ""
+		}
+	}
+	return strings.ToUpper(status)
+}
+
+// watermarkDecorator returns the decorator drawing the watermark of a
+// document with the given front matter status, or nil when there is
+// nothing to draw.
+func (c *Converter) watermarkDecorator(status string, f pageFont) folio.PageDecorator {
+	text := c.watermarkText(status)
+	if text == "" && c.watermarkImage == nil {
+		return nil
+	}
+	w := c.pdfOpts.Watermark
+	return func(_ folio.PageContext, page *folio.Page) {
+		var existing []byte
+		if cs := page.ContentStream(); cs != nil {
+			existing = bytes.Clone(cs.Bytes())
+		}
+		page.SetOpacity(cmp.Or(w.Opacity, DefaultWatermarkOpacity))
+		cs := page.ContentStream()
+		if c.watermarkImage != nil {
+			c.drawWatermarkImage(page)
+		}
+		if text != "" {
+			c.drawWatermarkText(page, text, f)
+		}
+
+		// The page methods register the resources of the watermark and
+		// append its operators to the content, which are moved behind or
+		// over it, each in its own graphics state.
+		full := cs.Bytes()
+		mark := "q\n" + strings.TrimSpace(string(full[len(existing):])) + "\nQ"
+		cs.ReplaceInBytes(string(full), "")
+		if len(existing) == 0 {
+			cs.AppendBytes([]byte(mark))
+		} else if w.Over {
+			cs.AppendBytes([]byte("q\n" + string(existing) + "\nQ\n" + mark))
+		} else {
+			cs.AppendBytes([]byte(mark + "\n" + string(existing)))
+		}
+	}
+}
+
+// drawWatermarkImage draws the watermark image at the center of page.
+func (c *Converter) drawWatermarkImage(page *folio.Page) {
+	img := c.watermarkImage
+	w := min(c.pageSize.Width*watermarkImageMax, float64(img.Width()))
+	h := w / img.AspectRatio()
+	if maxH := c.pageSize.Height * watermarkImageMax; h > maxH {
+		h = maxH
+		w = h * img.AspectRatio()
+	}
+	page.AddImage(img, (c.pageSize.Width-w)/2, (c.pageSize.Height-h)/2, w, h) //nolint:mnd // centered on the page
+}
+
+// drawWatermarkText draws text at the center of page, turned by the
+// watermark angle.
+func (c *Converter) drawWatermarkText(page *folio.Page, text string, f pageFont) {
+	w := c.pdfOpts.Watermark
+	rad := w.Angle * math.Pi / 180 //nolint:mnd // degrees to radians
+	cos, sin := math.Cos(rad), math.Sin(rad)
+
+	// The longest line through the center of the page at the angle.
+	span := math.Inf(1)
+	if cos != 0 {
+		span = c.pageSize.Width / math.Abs(cos)
+	}
+	if sin != 0 {
+		span = min(span, c.pageSize.Height/math.Abs(sin))
+	}
+	size := cmp.Or(w.FontSize, DefaultWatermarkFontSize)
+	if width := f.MeasureString(text, size); width > span*watermarkTextMax {
+		size *= span * watermarkTextMax / width
+	}
+
+	cs := page.ContentStream()
+	cs.SetFillColorRGB(c.watermarkColor[0], c.watermarkColor[1], c.watermarkColor[2])
+	cs.ConcatMatrix(cos, sin, -sin, cos, c.pageSize.Width/2, c.pageSize.Height/2)     //nolint:mnd // page center
+	f.draw(page, text, size, -f.MeasureString(text, size)/2, -size*watermarkCapRatio) //nolint:mnd // centered on the origin
+}
+
+// documentStatus returns the front matter status of the HTML document
+// htmlStr, or "" when it has none.
+func documentStatus(htmlStr string) string {
+	if !strings.Contains(htmlStr, `name="status"`) {
+		return ""
+	}
+	root, err := html.Parse(strings.NewReader(htmlStr))
+	if err != nil {
+		return ""
+	}
+	return metaContent(root, "status")
+}
+
+// metaContent returns the content of the first meta element of root with
+// the given name, or "" when there is none.
+func metaContent(root *html.Node, name string) string {
+	content := ""
+	found := false
+	walkElements(root, func(n *html.Node) {
+		if found || n.Data != "meta" {
+			return
+		}
+		var attrName, attrContent string
+		for _, a := range n.Attr {
+			switch a.Key {
+			case "name":
+				attrName = a.Val
+			case "content":
+				attrContent = a.Val
+			}
+		}
+		if attrName == name {
+			content, found = attrContent, true
+		}
+	})
+	return content
+}
@@ -0,0 +1,157 @@
+package processor
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/sgaunet/mdtohtml/pkg/converter"
+)
+
+// assetCopier copies the local files referenced by converted documents into
+// the mirrored location under the output directory. It is shared by the
+// workers of one batch run and copies every asset once: an asset is only
+// recorded as copied when the copy succeeds, so a failed copy is retried,
+// and reported, by the next document referencing it.
+type assetCopier struct {
+	inputDir string
+	options  ProcessOptions
+
+	mu     sync.Mutex
+	assets map[string]*assetCopy
+}
+
+// assetCopy is the state of one asset of an assetCopier. Its mutex is held
+// while the asset is copied, so documents referencing it wait for the copy.
+type assetCopy struct {
+	mu     sync.Mutex
+	copied bool
+}
+
+// newAssetCopier returns an assetCopier for a run over inputDir, or nil when
+// options.CopyAssets is not set.
+func newAssetCopier(inputDir string, options ProcessOptions) *assetCopier {
+	if !options.CopyAssets {
+		return nil
+	}
+	return &assetCopier{inputDir: inputDir, options: options, assets: make(map[string]*assetCopy)}
+}
+
+// copyReferenced copies the assets referenced by the Markdown file doc. It
+// returns a note for every asset that was skipped because it lies outside
+// the input directory.
+func (c *assetCopier) copyReferenced(doc string) ([]string, error) {
+	input, err := os.ReadFile(doc) //nolint:gosec // doc was found under the input directory
+	if err != nil {
+		return nil, fmt.Errorf("error reading file '%s': %w", doc, err)
+	}
+
+	var skipped []string
+	for _, ref := range converter.References(input) {
+		src, ok := c.assetPath(doc, ref)
+		if !ok {
+			continue
+		}
+		dst := filepath.Join(c.options.OutputDir, mustRel(c.inputDir, src))
+		if err := ValidateOutputPath(dst, c.options.OutputDir); err != nil {
+			skipped = append(skipped, fmt.Sprintf("asset '%s': outside '%s'", ref, c.inputDir))
+			continue
+		}
+		if err := c.copyOnce(src, dst); err != nil {
+			return skipped, err
+		}
+	}
+	return skipped, nil
+}
+
+// assetPath resolves ref, found in doc, to the path of an existing regular
+// file that is not itself converted by the batch. It reports false for
+// remote URLs, absolute paths, fragments and missing files.
+func (c *assetCopier) assetPath(doc, ref string) (string, bool) {
+	u, err := url.Parse(strings.TrimSpace(ref))
+	if err != nil || u.Scheme != "" || u.Host != "" || u.Path == "" || strings.HasPrefix(u.Path, "/") {
+		return "", false
+	}
+	src := filepath.Join(filepath.Dir(doc), filepath.FromSlash(u.Path))
+	if c.options.Matches(c.inputDir, src) {
+		return "", false
+	}
+	info, err := os.Stat(src)
+	if err != nil || !info.Mode().IsRegular() {
+		return "", false
+	}
+	return src, true
+}
+
+// copyOnce copies src to dst unless it was copied before in this run.
+func (c *assetCopier) copyOnce(src, dst string) error {
+	c.mu.Lock()
+	a, ok := c.assets[src]
+	if !ok {
+		a = &assetCopy{}
+		c.assets[src] = a
+	}
+	c.mu.Unlock()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.copied {
+		return nil
+	}
+	if err := copyAsset(src, dst); err != nil {
+		return err
+	}
+	a.copied = true
+	return nil
+}
+
+// mustRel returns path relative to dir, or path itself when no relative
+// path exists (which ValidateOutputPath then rejects).
+func mustRel(dir, path string) string {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return path
+	}
+	return rel
+}
+
+// copyAsset copies src to dst, creating dst's directory. Nothing is copied
+// when dst already is src, e.g. when the output directory is the input
+// directory.
+func copyAsset(src, dst string) error {
+	srcInfo, err := os.Stat(src)
+	if err != nil {
+		return fmt.Errorf("error reading asset '%s': %w", src, err)
+	}
+	if dstInfo, err := os.Stat(dst); err == nil && os.SameFile(srcInfo, dstInfo) {
+		return nil
+	}
+
+	const defaultDirMode = 0755
+	if err := os.MkdirAll(filepath.Dir(dst), defaultDirMode); err != nil {
+		return fmt.Errorf("error creating directory for asset '%s': %w", dst, err)
+	}
+	in, err := os.Open(src) //nolint:gosec // src was resolved below the input directory
+	if err != nil {
+		return fmt.Errorf("error reading asset '%s': %w", src, err)
+	}
+	defer func() { _ = in.Close() }()
+
+	const defaultFileMode = 0644
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, defaultFileMode) //nolint:gosec // validated output path
+	if err != nil {
+		return fmt.Errorf("error copying asset to '%s': %w", dst, err)
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return fmt.Errorf("error copying asset to '%s': %w", dst, err)
+	}
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("error copying asset to '%s': %w", dst, err)
+	}
+	return nil
+}
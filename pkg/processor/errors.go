@@ -1,6 +1,9 @@
 package processor
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	// ErrDirectoryNotExist is returned when a directory does not exist.
@@ -9,4 +12,45 @@ var (
 	ErrInvalidPattern = errors.New("invalid file pattern")
 	// ErrPathTraversal is returned when an output path escapes the output directory.
 	ErrPathTraversal = errors.New("path traversal detected")
-)
\ No newline at end of file
+)
+
+// FileError records the failure to convert a single input file.
+type FileError struct {
+	// Path is the input file that failed.
+	Path string
+	// Err is the underlying error.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *FileError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Path, e.Err)
+}
+
+// Unwrap returns the underlying error so errors.Is and errors.As see through FileError.
+func (e *FileError) Unwrap() error {
+	return e.Err
+}
+
+// BatchError aggregates the per-file failures of a batch run with
+// ProcessOptions.KeepGoing set. Failures are listed in input order.
+type BatchError struct {
+	// Failures holds one entry per file that failed.
+	Failures []*FileError
+	// Total is the number of files the batch attempted.
+	Total int
+}
+
+// Error implements the error interface.
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("%d of %d files failed to convert", len(e.Failures), e.Total)
+}
+
+// Unwrap returns the per-file errors so errors.Is and errors.As match any of them.
+func (e *BatchError) Unwrap() []error {
+	errs := make([]error, len(e.Failures))
+	for i, f := range e.Failures {
+		errs[i] = f
+	}
+	return errs
+}
@@ -1,6 +1,7 @@
 package processor
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -14,6 +15,8 @@ type FileProcessor struct {
 }
 
 // NewFileProcessor creates a new file processor with the given converter.
+// The converter is shared by all batch workers and must therefore be safe
+// for concurrent use; the converters in this module are.
 func NewFileProcessor(conv converter.Converter) *FileProcessor {
 	return &FileProcessor{
 		converter: conv,
@@ -21,6 +24,9 @@ func NewFileProcessor(conv converter.Converter) *FileProcessor {
 }
 
 // ProcessDirectory processes all matching files in a directory.
+// Up to options.Concurrency files are converted in parallel. With
+// options.KeepGoing every file is attempted, a summary of failures is
+// printed, and the returned error wraps a *BatchError.
 func (p *FileProcessor) ProcessDirectory(dir string, options ProcessOptions) error {
 	// Validate input directory
 	if _, err := os.Stat(dir); os.IsNotExist(err) {
@@ -42,7 +48,7 @@ func (p *FileProcessor) ProcessDirectory(dir string, options ProcessOptions) err
 	}
 
 	// Find files to process
-	files, err := p.findFiles(dir, options)
+	files, err := FindFiles(dir, options)
 	if err != nil {
 		return fmt.Errorf("error finding files matching '%s' in '%s': %w", options.Pattern, dir, err)
 	}
@@ -52,25 +58,45 @@ func (p *FileProcessor) ProcessDirectory(dir string, options ProcessOptions) err
 		return nil
 	}
 
-	// Process each file
+	fmt.Printf("Converting %d files...\n", len(files))
+	if err := p.ProcessFiles(files, dir, options); err != nil {
+		return err
+	}
+
+	fmt.Printf("Successfully converted %d files to '%s'\n", len(files), options.OutputDir)
+	return nil
+}
+
+// ProcessFiles converts the given files, which must be inside dir, to the
+// same output paths ProcessDirectory would use. It is used to rebuild only
+// the files that changed, e.g. in watch mode.
+func (p *FileProcessor) ProcessFiles(files []string, dir string, options ProcessOptions) error {
+	if len(files) == 0 {
+		return nil
+	}
 	ext := options.OutputExt
 	if ext == "" {
 		ext = DefaultOutputExt
 	}
-	fmt.Printf("Converting %d files...\n", len(files))
-	for _, file := range files {
-		if err := p.processFile(file, dir, options.OutputDir, ext); err != nil {
-			return fmt.Errorf("batch processing '%s': %w", dir, err)
+	if err := p.processFiles(files, dir, ext, options); err != nil {
+		var batchErr *BatchError
+		if errors.As(err, &batchErr) {
+			printSummary(batchErr)
 		}
+		return fmt.Errorf("batch processing '%s': %w", dir, err)
 	}
-
-	fmt.Printf("Successfully converted %d files to '%s'\n", len(files), options.OutputDir)
 	return nil
 }
 
-func (p *FileProcessor) findFiles(dir string, options ProcessOptions) ([]string, error) {
+// FindFiles returns the files in dir that ProcessDirectory would convert:
+// those whose base name matches options.Pattern, including files in
+// subdirectories when options.Recursive is set. Other options are ignored.
+func FindFiles(dir string, options ProcessOptions) ([]string, error) {
+	if _, err := filepath.Match(options.Pattern, ""); err != nil {
+		return nil, fmt.Errorf("%w '%s': %w", ErrInvalidPattern, options.Pattern, err)
+	}
 	if options.Recursive {
-		return p.findFilesRecursive(dir, options.Pattern)
+		return findFilesRecursive(dir, options.Pattern)
 	}
 	files, err := filepath.Glob(filepath.Join(dir, options.Pattern))
 	if err != nil {
@@ -79,7 +105,7 @@ func (p *FileProcessor) findFiles(dir string, options ProcessOptions) ([]string,
 	return files, nil
 }
 
-func (p *FileProcessor) findFilesRecursive(dir, pattern string) ([]string, error) {
+func findFilesRecursive(dir, pattern string) ([]string, error) {
 	var files []string
 	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, walkErr error) error {
 		if walkErr != nil {
@@ -103,26 +129,32 @@ func (p *FileProcessor) findFilesRecursive(dir, pattern string) ([]string, error
 	return files, nil
 }
 
-func (p *FileProcessor) processFile(file, inputDir, outputDir, ext string) error {
+// processFile converts a single file and, when assets is not nil, copies the
+// files it references. It does not print anything so that processFiles can
+// report progress in input order.
+func (p *FileProcessor) processFile(file, inputDir, outputDir, ext string, assets *assetCopier) fileResult {
 	outputPath := GetOutputPathExt(file, inputDir, outputDir, ext)
 
 	if err := ValidateOutputPath(outputPath, outputDir); err != nil {
-		return err
+		return fileResult{err: err}
 	}
 
 	// Create subdirectories if needed
 	const defaultDirMode = 0755
 	if err := os.MkdirAll(filepath.Dir(outputPath), defaultDirMode); err != nil {
 		if os.IsPermission(err) {
-			return fmt.Errorf("permission denied creating directory for '%s': %w", outputPath, err)
+			return fileResult{err: fmt.Errorf("permission denied creating directory for '%s': %w", outputPath, err)}
 		}
-		return fmt.Errorf("error creating directory for '%s': %w", outputPath, err)
+		return fileResult{err: fmt.Errorf("error creating directory for '%s': %w", outputPath, err)}
 	}
 
-	fmt.Printf("Converting %s -> %s\n", file, outputPath)
 	if err := p.converter.ConvertFile(file, outputPath); err != nil {
-		return fmt.Errorf("error converting '%s': %w", file, err)
+		return fileResult{outputPath: outputPath, err: fmt.Errorf("error converting '%s': %w", file, err)}
 	}
 
-	return nil
+	if assets == nil {
+		return fileResult{outputPath: outputPath}
+	}
+	notes, err := assets.copyReferenced(file)
+	return fileResult{outputPath: outputPath, notes: notes, err: err}
 }
\ No newline at end of file
@@ -0,0 +1,120 @@
+package processor
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"text/tabwriter"
+)
+
+// fileResult records the outcome of converting a single file.
+type fileResult struct {
+	// outputPath is empty when the file failed before conversion started
+	// (e.g. path traversal), in which case no progress line is printed.
+	outputPath string
+	// notes are printed below the progress line, e.g. skipped assets.
+	notes []string
+	err   error
+}
+
+// workerCount returns the number of workers to start for n files.
+// Values below 1 default to runtime.GOMAXPROCS(0).
+func workerCount(concurrency, n int) int {
+	if concurrency < 1 {
+		concurrency = runtime.GOMAXPROCS(0)
+	}
+	return min(concurrency, n)
+}
+
+// processFiles converts files with a bounded pool of workers sharing the
+// processor's converter.
+//
+// Progress lines are printed in input order regardless of completion order.
+// Unless options.KeepGoing is set, no further files are dispatched once a file
+// fails; in-flight files are allowed to finish. Because files are dispatched
+// in order, the error returned is always the one for the lowest-indexed
+// failing file, so the output of a failed run does not depend on scheduling.
+// With KeepGoing every file is attempted and all failures are returned as a
+// *BatchError.
+func (p *FileProcessor) processFiles(files []string, inputDir, ext string, options ProcessOptions) error {
+	results := make([]fileResult, len(files))
+	jobs := make(chan int)
+	completed := make(chan int)
+	var failed atomic.Bool
+	assets := newAssetCopier(inputDir, options)
+
+	var wg sync.WaitGroup
+	for range workerCount(options.Concurrency, len(files)) {
+		wg.Go(func() {
+			for i := range jobs {
+				results[i] = p.processFile(files[i], inputDir, options.OutputDir, ext, assets)
+				completed <- i
+			}
+		})
+	}
+
+	go func() {
+		defer close(jobs)
+		for i := range files {
+			if failed.Load() && !options.KeepGoing {
+				return
+			}
+			jobs <- i
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(completed)
+	}()
+
+	finished := make([]bool, len(files))
+	next := 0
+	var batchErr BatchError
+	for i := range completed {
+		finished[i] = true
+		if results[i].err != nil {
+			failed.Store(true)
+		}
+		// Report the contiguous prefix of finished files. Without keepGoing,
+		// stop at the first failure so the log matches a sequential run.
+		for next < len(files) && finished[next] && (options.KeepGoing || len(batchErr.Failures) == 0) {
+			res := results[next]
+			if res.outputPath != "" {
+				fmt.Printf("Converting %s -> %s\n", files[next], res.outputPath)
+			}
+			for _, note := range res.notes {
+				fmt.Printf("  Skipped %s\n", note)
+			}
+			if res.err != nil {
+				batchErr.Failures = append(batchErr.Failures, &FileError{Path: files[next], Err: res.err})
+			}
+			next++
+		}
+	}
+
+	switch {
+	case len(batchErr.Failures) == 0:
+		return nil
+	case !options.KeepGoing:
+		return batchErr.Failures[0].Err
+	default:
+		batchErr.Total = len(files)
+		return &batchErr
+	}
+}
+
+// printSummary writes a table of the failures in batchErr to stdout.
+func printSummary(batchErr *BatchError) {
+	fmt.Printf("\nSummary: %d converted, %d failed\n",
+		batchErr.Total-len(batchErr.Failures), len(batchErr.Failures))
+	const padding = 2
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, padding, ' ', 0)
+	_, _ = fmt.Fprintln(w, "FILE\tERROR")
+	for _, f := range batchErr.Failures {
+		_, _ = fmt.Fprintf(w, "%s\t%v\n", f.Path, f.Err)
+	}
+	_ = w.Flush()
+}
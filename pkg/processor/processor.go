@@ -30,6 +30,35 @@ type ProcessOptions struct {
 	// OutputExt is the extension applied to converted files (e.g., ".html", ".pdf").
 	// Empty defaults to DefaultOutputExt.
 	OutputExt string
+
+	// Concurrency is the maximum number of files converted in parallel.
+	// Values below 1 default to runtime.GOMAXPROCS(0).
+	Concurrency int
+
+	// KeepGoing attempts every file even after a failure. Failures are
+	// collected and returned together as a *BatchError.
+	KeepGoing bool
+
+	// CopyAssets copies the local files that converted documents reference
+	// (images, linked PDFs, downloads) to the same relative location under
+	// OutputDir, so that the references keep working. Each asset is copied
+	// once per run. Assets outside the input directory are skipped with a
+	// note, and references to files that the batch converts are ignored.
+	CopyAssets bool
+}
+
+// Matches reports whether ProcessDirectory(dir, o) would convert path: its
+// base name must match Pattern and, unless Recursive is set, it must be
+// directly inside dir.
+func (o ProcessOptions) Matches(dir, path string) bool {
+	if matched, err := filepath.Match(o.Pattern, filepath.Base(path)); err != nil || !matched {
+		return false
+	}
+	rel, err := filepath.Rel(dir, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return false
+	}
+	return o.Recursive || !strings.ContainsRune(rel, filepath.Separator)
 }
 
 // FileInfo represents information about a file to be processed.
@@ -440,6 +440,74 @@ func TestFileProcessor_FindFiles_EdgeCases(t *testing.T) {
 	}
 }
 
+// TestFindFiles tests the file discovery shared by batch and validate
+func TestFindFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, name := range []string{"a.md", "b.markdown", "notes.txt", "sub/c.md", "sub/deep/d.md"} {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("# Test"), 0644); err != nil {
+			t.Fatalf("Failed to create file: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		options  processor.ProcessOptions
+		expected []string
+	}{
+		{
+			name:     "top level only",
+			options:  processor.ProcessOptions{Pattern: "*.md"},
+			expected: []string{"a.md"},
+		},
+		{
+			name:     "recursive",
+			options:  processor.ProcessOptions{Pattern: "*.md", Recursive: true},
+			expected: []string{"a.md", "sub/c.md", "sub/deep/d.md"},
+		},
+		{
+			name:     "other pattern",
+			options:  processor.ProcessOptions{Pattern: "*.markdown", Recursive: true},
+			expected: []string{"b.markdown"},
+		},
+		{
+			name:     "no matches",
+			options:  processor.ProcessOptions{Pattern: "*.xyz"},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, err := processor.FindFiles(tmpDir, tt.options)
+			if err != nil {
+				t.Fatalf("FindFiles() unexpected error = %v", err)
+			}
+			var got []string
+			for _, f := range files {
+				if !tt.options.Matches(tmpDir, f) {
+					t.Errorf("FindFiles() returned %s, which Matches rejects", f)
+				}
+				rel, _ := filepath.Rel(tmpDir, f)
+				got = append(got, filepath.ToSlash(rel))
+			}
+			if strings.Join(got, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("FindFiles() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		_, err := processor.FindFiles(tmpDir, processor.ProcessOptions{Pattern: "[", Recursive: true})
+		if !errors.Is(err, processor.ErrInvalidPattern) {
+			t.Errorf("FindFiles() error = %v, want ErrInvalidPattern", err)
+		}
+	})
+}
+
 // TestFileProcessor_LargeFiles tests processing of large files
 func TestFileProcessor_LargeFiles(t *testing.T) {
 	if testing.Short() {
@@ -667,4 +735,373 @@ func (m *MockFailingConverter) Convert(input []byte) ([]byte, error) {
 
 func (m *MockFailingConverter) ConvertFile(inputPath, outputPath string) error {
 	return fmt.Errorf("mock file conversion error")
-}
\ No newline at end of file
+}
+
+// TestFileProcessor_ProcessDirectory_WorkerPool tests that every file is converted
+// when several workers share one converter
+func TestFileProcessor_ProcessDirectory_WorkerPool(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+	outputDir := filepath.Join(tmpDir, "output")
+
+	if err := os.MkdirAll(inputDir, 0755); err != nil {
+		t.Fatalf("Failed to create input directory: %v", err)
+	}
+
+	const fileCount = 25
+	for i := 0; i < fileCount; i++ {
+		path := filepath.Join(inputDir, fmt.Sprintf("doc%02d.md", i))
+		if err := os.WriteFile(path, []byte(fmt.Sprintf("# Doc %d\n\nBody.", i)), 0644); err != nil {
+			t.Fatalf("Failed to create test file %d: %v", i, err)
+		}
+	}
+
+	for _, concurrency := range []int{0, 1, 4, 64} {
+		t.Run(fmt.Sprintf("concurrency=%d", concurrency), func(t *testing.T) {
+			out := filepath.Join(outputDir, fmt.Sprintf("c%d", concurrency))
+			proc := processor.NewFileProcessor(converter.NewCompleteConverter(converter.DefaultOptions()))
+
+			options := processor.ProcessOptions{
+				OutputDir:   out,
+				Pattern:     "*.md",
+				Concurrency: concurrency,
+			}
+			if err := proc.ProcessDirectory(inputDir, options); err != nil {
+				t.Fatalf("ProcessDirectory() error = %v", err)
+			}
+
+			if got := countHTMLFiles(t, out); got != fileCount {
+				t.Errorf("Expected %d output files, got %d", fileCount, got)
+			}
+		})
+	}
+}
+
+// TestFileProcessor_ProcessDirectory_DeterministicError tests that the reported
+// error is always the one for the first failing file in input order
+func TestFileProcessor_ProcessDirectory_DeterministicError(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+
+	if err := os.MkdirAll(inputDir, 0755); err != nil {
+		t.Fatalf("Failed to create input directory: %v", err)
+	}
+
+	for i := 0; i < 20; i++ {
+		path := filepath.Join(inputDir, fmt.Sprintf("doc%02d.md", i))
+		if err := os.WriteFile(path, []byte("# Doc"), 0644); err != nil {
+			t.Fatalf("Failed to create test file %d: %v", i, err)
+		}
+	}
+
+	conv := &MockSelectiveConverter{fail: map[string]bool{"doc05.md": true, "doc11.md": true}}
+	proc := processor.NewFileProcessor(conv)
+
+	for run := 0; run < 10; run++ {
+		options := processor.ProcessOptions{
+			OutputDir:   filepath.Join(tmpDir, fmt.Sprintf("output%d", run)),
+			Pattern:     "*.md",
+			Concurrency: 8,
+		}
+		err := proc.ProcessDirectory(inputDir, options)
+		if err == nil {
+			t.Fatal("Expected error from failing converter")
+		}
+		if !strings.Contains(err.Error(), "doc05.md") {
+			t.Fatalf("run %d: expected error for doc05.md, got: %v", run, err)
+		}
+	}
+}
+
+// TestFileProcessor_ProcessDirectory_KeepGoing tests that every file is attempted
+// and failures are aggregated into a BatchError
+func TestFileProcessor_ProcessDirectory_KeepGoing(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+	outputDir := filepath.Join(tmpDir, "output")
+
+	if err := os.MkdirAll(inputDir, 0755); err != nil {
+		t.Fatalf("Failed to create input directory: %v", err)
+	}
+
+	for i := 0; i < 10; i++ {
+		path := filepath.Join(inputDir, fmt.Sprintf("doc%02d.md", i))
+		if err := os.WriteFile(path, []byte("# Doc"), 0644); err != nil {
+			t.Fatalf("Failed to create test file %d: %v", i, err)
+		}
+	}
+
+	conv := &MockSelectiveConverter{fail: map[string]bool{"doc07.md": true, "doc02.md": true}}
+	proc := processor.NewFileProcessor(conv)
+
+	options := processor.ProcessOptions{
+		OutputDir:   outputDir,
+		Pattern:     "*.md",
+		Concurrency: 4,
+		KeepGoing:   true,
+	}
+	err := proc.ProcessDirectory(inputDir, options)
+	if err == nil {
+		t.Fatal("Expected error from failing converter")
+	}
+
+	var batchErr *processor.BatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("Expected error wrapping *BatchError, got: %v", err)
+	}
+	if batchErr.Total != 10 {
+		t.Errorf("BatchError.Total = %d, want 10", batchErr.Total)
+	}
+	if len(batchErr.Failures) != 2 {
+		t.Fatalf("Expected 2 failures, got %d: %v", len(batchErr.Failures), batchErr)
+	}
+	for i, want := range []string{"doc02.md", "doc07.md"} {
+		if got := filepath.Base(batchErr.Failures[i].Path); got != want {
+			t.Errorf("Failures[%d].Path = %q, want %q", i, got, want)
+		}
+	}
+	if !errors.Is(err, errMockConversion) {
+		t.Errorf("Expected error wrapping errMockConversion, got: %v", err)
+	}
+
+	// All successful files were still written
+	if got := countHTMLFiles(t, outputDir); got != 8 {
+		t.Errorf("Expected 8 output files, got %d", got)
+	}
+}
+
+// TestBatchError_PathTraversal tests that errors.Is reaches sentinel errors
+// wrapped inside individual file failures
+func TestBatchError_PathTraversal(t *testing.T) {
+	traversal := processor.ValidateOutputPath("/output/../etc/x.html", "/output")
+	err := fmt.Errorf("batch: %w", &processor.BatchError{
+		Failures: []*processor.FileError{
+			{Path: "a.md", Err: errMockConversion},
+			{Path: "b.md", Err: traversal},
+		},
+		Total: 3,
+	})
+
+	if !errors.Is(err, processor.ErrPathTraversal) {
+		t.Errorf("Expected error wrapping ErrPathTraversal, got: %v", err)
+	}
+
+	var fileErr *processor.FileError
+	if !errors.As(err, &fileErr) || fileErr.Path != "a.md" {
+		t.Errorf("errors.As(*FileError) = %v, want first failure a.md", fileErr)
+	}
+
+	if !strings.Contains(err.Error(), "2 of 3 files failed") {
+		t.Errorf("Unexpected error message: %v", err)
+	}
+}
+
+var errMockConversion = errors.New("mock conversion error")
+
+// Mock converter that fails for the listed base names
+type MockSelectiveConverter struct {
+	fail map[string]bool
+}
+
+func (m *MockSelectiveConverter) Convert(input []byte) ([]byte, error) {
+	return input, nil
+}
+
+func (m *MockSelectiveConverter) ConvertFile(inputPath, outputPath string) error {
+	if m.fail[filepath.Base(inputPath)] {
+		return fmt.Errorf("%w for %s", errMockConversion, filepath.Base(inputPath))
+	}
+	return os.WriteFile(outputPath, []byte("ok"), 0644)
+}
+
+// TestProcessOptions_Matches tests which paths a batch run would pick up
+func TestProcessOptions_Matches(t *testing.T) {
+	dir := filepath.Join("docs")
+	tests := []struct {
+		name      string
+		path      string
+		pattern   string
+		recursive bool
+		want      bool
+	}{
+		{name: "top-level match", path: filepath.Join(dir, "a.md"), pattern: "*.md", want: true},
+		{name: "pattern mismatch", path: filepath.Join(dir, "a.txt"), pattern: "*.md", want: false},
+		{name: "nested non-recursive", path: filepath.Join(dir, "sub", "a.md"), pattern: "*.md", want: false},
+		{name: "nested recursive", path: filepath.Join(dir, "sub", "a.md"), pattern: "*.md", recursive: true, want: true},
+		{name: "outside dir", path: filepath.Join("other", "a.md"), pattern: "*.md", recursive: true, want: false},
+		{name: "invalid pattern", path: filepath.Join(dir, "a.md"), pattern: "[", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := processor.ProcessOptions{Pattern: tt.pattern, Recursive: tt.recursive}
+			if got := options.Matches(dir, tt.path); got != tt.want {
+				t.Errorf("Matches(%q, %q) = %v, want %v", dir, tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestFileProcessor_ProcessFiles tests that only the listed files are converted
+func TestFileProcessor_ProcessFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+	outputDir := filepath.Join(tmpDir, "output")
+
+	if err := os.MkdirAll(filepath.Join(inputDir, "sub"), 0755); err != nil {
+		t.Fatalf("Failed to create input directory: %v", err)
+	}
+	for _, name := range []string{"a.md", "b.md", filepath.Join("sub", "c.md")} {
+		if err := os.WriteFile(filepath.Join(inputDir, name), []byte("# Doc"), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+
+	proc := processor.NewFileProcessor(&MockSelectiveConverter{})
+	options := processor.ProcessOptions{OutputDir: outputDir, Pattern: "*.md", Recursive: true}
+
+	if err := proc.ProcessFiles(nil, inputDir, options); err != nil {
+		t.Fatalf("ProcessFiles(nil) error = %v", err)
+	}
+	if _, err := os.Stat(outputDir); !os.IsNotExist(err) {
+		t.Errorf("Expected no output directory for an empty file list, got %v", err)
+	}
+
+	files := []string{filepath.Join(inputDir, "b.md"), filepath.Join(inputDir, "sub", "c.md")}
+	if err := proc.ProcessFiles(files, inputDir, options); err != nil {
+		t.Fatalf("ProcessFiles() error = %v", err)
+	}
+	for name, want := range map[string]bool{
+		"a.html":                       false,
+		"b.html":                       true,
+		filepath.Join("sub", "c.html"): true,
+	} {
+		_, err := os.Stat(filepath.Join(outputDir, name))
+		if got := err == nil; got != want {
+			t.Errorf("Output %s exists = %v, want %v", name, got, want)
+		}
+	}
+
+	failing := processor.NewFileProcessor(&MockSelectiveConverter{fail: map[string]bool{"a.md": true}})
+	err := failing.ProcessFiles([]string{filepath.Join(inputDir, "a.md")}, inputDir, options)
+	if !errors.Is(err, errMockConversion) {
+		t.Errorf("ProcessFiles() error = %v, want %v", err, errMockConversion)
+	}
+}
+
+// TestFileProcessor_ProcessDirectory_CopyAssets tests that referenced local
+// files are mirrored into the output directory
+func TestFileProcessor_ProcessDirectory_CopyAssets(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+	files := map[string]string{
+		"index.md": "# Index\n\n![logo](img/logo.png) [Manual](files/manual%20v1.pdf#page=2)\n" +
+			"[Guide](guide/setup.md) ![remote](https://example.com/x.png) ![gone](img/gone.png)\n" +
+			"![shared](../shared.png)\n",
+		"guide/setup.md":      "# Setup\n\n![logo](../img/logo.png)\n\n<img src=\"diagram.svg\">\n",
+		"guide/diagram.svg":   "<svg/>",
+		"img/logo.png":        "PNG",
+		"img/unused.png":      "PNG",
+		"files/manual v1.pdf": "PDF",
+		"../shared.png":       "PNG",
+	}
+	for name, content := range files {
+		path := filepath.Join(inputDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		copyAssets bool
+		want       map[string]bool
+	}{
+		{
+			name:       "enabled",
+			copyAssets: true,
+			want: map[string]bool{
+				"img/logo.png":        true,
+				"files/manual v1.pdf": true,
+				"guide/diagram.svg":   true,
+				"img/unused.png":      false,
+				"guide/setup.md":      false,
+			},
+		},
+		{
+			name:       "disabled",
+			copyAssets: false,
+			want: map[string]bool{
+				"img/logo.png":        false,
+				"files/manual v1.pdf": false,
+				"guide/diagram.svg":   false,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputDir := filepath.Join(tmpDir, "output-"+tt.name)
+			proc := processor.NewFileProcessor(&MockSelectiveConverter{})
+			options := processor.ProcessOptions{
+				OutputDir:   outputDir,
+				Pattern:     "*.md",
+				Recursive:   true,
+				Concurrency: 4,
+				CopyAssets:  tt.copyAssets,
+			}
+			if err := proc.ProcessDirectory(inputDir, options); err != nil {
+				t.Fatalf("ProcessDirectory() error = %v", err)
+			}
+			for name, want := range tt.want {
+				_, err := os.Stat(filepath.Join(outputDir, filepath.FromSlash(name)))
+				if got := err == nil; got != want {
+					t.Errorf("Asset %s copied = %v, want %v", name, got, want)
+				}
+			}
+		})
+	}
+
+	t.Run("output directory is the input directory", func(t *testing.T) {
+		proc := processor.NewFileProcessor(&MockSelectiveConverter{})
+		options := processor.ProcessOptions{OutputDir: inputDir, Pattern: "*.md", CopyAssets: true}
+		if err := proc.ProcessDirectory(inputDir, options); err != nil {
+			t.Fatalf("ProcessDirectory() error = %v", err)
+		}
+		data, err := os.ReadFile(filepath.Join(inputDir, "img", "logo.png"))
+		if err != nil || string(data) != "PNG" {
+			t.Errorf("Asset changed when copied onto itself: %q, %v", data, err)
+		}
+	})
+
+	t.Run("failed copy is reported by every referencing document", func(t *testing.T) {
+		outputDir := filepath.Join(tmpDir, "output-blocked")
+		if err := os.MkdirAll(outputDir, 0755); err != nil {
+			t.Fatalf("Failed to create output directory: %v", err)
+		}
+		// A regular file where the asset directory belongs makes the copy fail.
+		if err := os.WriteFile(filepath.Join(outputDir, "img"), nil, 0644); err != nil {
+			t.Fatalf("Failed to create blocking file: %v", err)
+		}
+		proc := processor.NewFileProcessor(&MockSelectiveConverter{})
+		options := processor.ProcessOptions{
+			OutputDir:   outputDir,
+			Pattern:     "*.md",
+			Recursive:   true,
+			Concurrency: 4,
+			CopyAssets:  true,
+			KeepGoing:   true,
+		}
+		err := proc.ProcessDirectory(inputDir, options)
+		var batchErr *processor.BatchError
+		if !errors.As(err, &batchErr) {
+			t.Fatalf("ProcessDirectory() error = %v, want a *BatchError", err)
+		}
+		if len(batchErr.Failures) != 2 {
+			t.Errorf("Failures = %v, want one for each document referencing img/logo.png", batchErr.Failures)
+		}
+	})
+}
@@ -0,0 +1,6 @@
+package server
+
+import "errors"
+
+// ErrServe is returned when the preview server cannot open its source directory.
+var ErrServe = errors.New("preview server failed")
@@ -0,0 +1,291 @@
+// Package server implements a local preview server that renders Markdown
+// files to HTML on request and reloads open pages when their sources change.
+package server
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/sgaunet/mdtohtml/pkg/converter"
+)
+
+// ReloadPath is the server-sent events endpoint polled by the live-reload script.
+const ReloadPath = "/__mdtohtml/livereload"
+
+// reloadScript is injected into every rendered page. It reloads the page when
+// the server reports a change.
+const reloadScript = `<script>
+(function () {
+  var source = new EventSource("` + ReloadPath + `");
+  source.onmessage = function () { location.reload(); };
+})();
+</script>
+`
+
+// indexFiles are rendered in place of a directory listing, in order of preference.
+var indexFiles = []string{"index.md", "README.md"}
+
+// Server renders the Markdown files below a directory and serves every other
+// file in it as is. It implements http.Handler and is safe for concurrent use.
+//
+//   - "/doc.md" and "/doc.html" render doc.md.
+//   - "/dir/" renders dir/index.md or dir/README.md, or lists the directory.
+//   - ReloadPath streams an event to every open page when Reload is called.
+type Server struct {
+	root *os.Root
+
+	mu   sync.RWMutex
+	conv converter.Converter
+
+	clientsMu sync.Mutex
+	clients   map[chan struct{}]struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// New returns a Server for the files below dir that renders Markdown with
+// conv, typically a [converter.CompleteConverter]. Requests cannot reach files
+// outside dir, including through symbolic links.
+func New(dir string, conv converter.Converter) (*Server, error) {
+	root, err := os.OpenRoot(dir)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrServe, err)
+	}
+	return &Server{
+		root:    root,
+		conv:    conv,
+		clients: make(map[chan struct{}]struct{}),
+		done:    make(chan struct{}),
+	}, nil
+}
+
+// SetConverter replaces the converter used for subsequent requests, e.g.
+// after the stylesheet changed.
+func (s *Server) SetConverter(conv converter.Converter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.conv = conv
+}
+
+// Reload tells every open page to reload itself.
+func (s *Server) Reload() {
+	s.clientsMu.Lock()
+	defer s.clientsMu.Unlock()
+	for ch := range s.clients {
+		select {
+		case ch <- struct{}{}:
+		default: // a reload is already pending for this client
+		}
+	}
+}
+
+// Close ends the open live-reload streams and releases the source directory.
+func (s *Server) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		close(s.done)
+		err = s.root.Close()
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrServe, err)
+	}
+	return nil
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	if r.URL.Path == ReloadPath {
+		s.serveReload(w, r)
+		return
+	}
+
+	name := strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/")
+	if name == "" {
+		name = "."
+	}
+	info, err := s.root.Stat(name)
+	switch {
+	case err == nil && info.IsDir():
+		s.serveDir(w, r, name)
+	case err == nil && isMarkdown(name):
+		s.serveMarkdown(w, r, name)
+	case err == nil:
+		http.ServeFileFS(w, r, s.root.FS(), name)
+	case strings.HasSuffix(name, ".html") && s.isFile(strings.TrimSuffix(name, ".html")+".md"):
+		// Links rewritten for batch output point at .html files.
+		s.serveMarkdown(w, r, strings.TrimSuffix(name, ".html")+".md")
+	case errors.Is(err, fs.ErrNotExist):
+		http.NotFound(w, r)
+	default:
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+	}
+}
+
+// serveDir renders the directory's index file or lists its content.
+// Directory URLs get a trailing slash so that relative links resolve.
+func (s *Server) serveDir(w http.ResponseWriter, r *http.Request, name string) {
+	if !strings.HasSuffix(r.URL.Path, "/") {
+		http.Redirect(w, r, path.Base(r.URL.Path)+"/", http.StatusMovedPermanently)
+		return
+	}
+	for _, index := range indexFiles {
+		if p := path.Join(name, index); s.isFile(p) {
+			s.serveMarkdown(w, r, p)
+			return
+		}
+	}
+
+	entries, err := fs.ReadDir(s.root.FS(), name)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+	s.render(w, []byte(listing(name, entries)))
+}
+
+// serveMarkdown renders the Markdown file name.
+func (s *Server) serveMarkdown(w http.ResponseWriter, _ *http.Request, name string) {
+	input, err := s.root.ReadFile(name)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+	s.render(w, input)
+}
+
+// render converts Markdown input and writes the page with the live-reload
+// script added.
+func (s *Server) render(w http.ResponseWriter, input []byte) {
+	s.mu.RLock()
+	conv := s.conv
+	s.mu.RUnlock()
+
+	output, err := conv.Convert(input)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("conversion failed: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	_, _ = w.Write(injectReloadScript(output))
+}
+
+// serveReload streams a server-sent event each time Reload is called until
+// the client disconnects or the server is closed.
+func (s *Server) serveReload(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	ch := make(chan struct{}, 1)
+	s.clientsMu.Lock()
+	s.clients[ch] = struct{}{}
+	s.clientsMu.Unlock()
+	defer func() {
+		s.clientsMu.Lock()
+		delete(s.clients, ch)
+		s.clientsMu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-ch:
+			_, _ = w.Write([]byte("data: reload\n\n"))
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// isFile reports whether name is a regular file below the root.
+func (s *Server) isFile(name string) bool {
+	info, err := s.root.Stat(name)
+	return err == nil && info.Mode().IsRegular()
+}
+
+// isMarkdown reports whether name has a Markdown extension.
+func isMarkdown(name string) bool {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".md", ".markdown":
+		return true
+	}
+	return false
+}
+
+// injectReloadScript inserts the live-reload script before the closing body
+// tag, or appends it when there is none.
+func injectReloadScript(page []byte) []byte {
+	i := bytes.LastIndex(bytes.ToLower(page), []byte("</body>"))
+	if i < 0 {
+		return append(page, reloadScript...)
+	}
+	out := make([]byte, 0, len(page)+len(reloadScript))
+	out = append(out, page[:i]...)
+	out = append(out, reloadScript...)
+	return append(out, page[i:]...)
+}
+
+// listing returns a Markdown page linking to the subdirectories and Markdown
+// files of the directory name. Hidden entries are left out.
+func listing(name string, entries []fs.DirEntry) string {
+	var sb strings.Builder
+	title := "/"
+	if name != "." {
+		title += name + "/"
+	}
+	fmt.Fprintf(&sb, "# Index of %s\n\n", escapeMarkdown(title))
+	if name != "." {
+		sb.WriteString("- [../](../)\n")
+	}
+	entries = slices.DeleteFunc(slices.Clone(entries), func(e fs.DirEntry) bool {
+		return strings.HasPrefix(e.Name(), ".") || (!e.IsDir() && !isMarkdown(e.Name()))
+	})
+	for _, e := range entries {
+		label, target := e.Name(), url.PathEscape(e.Name())
+		if e.IsDir() {
+			label += "/"
+			target += "/"
+		}
+		fmt.Fprintf(&sb, "- [%s](%s)\n", escapeMarkdown(label), target)
+	}
+	return sb.String()
+}
+
+// escapeMarkdown backslash-escapes the characters that could turn a file name
+// into Markdown markup.
+func escapeMarkdown(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune("\\`*_{}[]()<>#+-.!|~", r) {
+			sb.WriteByte('\\')
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// Compile-time interface check.
+var _ http.Handler = (*Server)(nil)
@@ -0,0 +1,270 @@
+package server_test
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sgaunet/mdtohtml/pkg/converter"
+	"github.com/sgaunet/mdtohtml/pkg/server"
+)
+
+// newTestServer serves a directory holding a few Markdown files and an image.
+func newTestServer(t *testing.T, opts converter.Options) (*server.Server, *httptest.Server) {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"README.md":             "# Home\n\nSee [guide](guide.md).\n",
+		"guide.md":              "# Guide\n\n<span class=\"raw\">raw</span> \"quoted\"\n",
+		"logo.png":              "PNG",
+		"sub/page.markdown":     "# Page\n",
+		"sub/notes.txt":         "notes",
+		"sub/.hidden.md":        "# Hidden\n",
+		"sub/nested/deep.md":    "# Deep\n",
+		"empty/.keep":           "",
+		"index-first/index.md":  "# Index\n",
+		"index-first/README.md": "# Readme\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+	// A file outside the served directory, reachable only through a symlink.
+	outside := filepath.Join(t.TempDir(), "secret.md")
+	if err := os.WriteFile(outside, []byte("# Secret\n"), 0644); err != nil {
+		t.Fatalf("Failed to create secret: %v", err)
+	}
+	if err := os.Symlink(outside, filepath.Join(dir, "link.md")); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+
+	srv, err := server.New(dir, converter.NewCompleteConverter(opts))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	ts := httptest.NewServer(srv)
+	t.Cleanup(func() {
+		_ = srv.Close()
+		ts.Close()
+	})
+	return srv, ts
+}
+
+func get(t *testing.T, url string) (int, string) {
+	t.Helper()
+	resp, err := http.Get(url) //nolint:noctx // test request
+	if err != nil {
+		t.Fatalf("GET %s error = %v", url, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("Failed to read body: %v", err)
+	}
+	return resp.StatusCode, string(body)
+}
+
+// TestServer_ServeHTTP tests rendering, static files and directory handling
+func TestServer_ServeHTTP(t *testing.T) {
+	_, ts := newTestServer(t, converter.DefaultOptions())
+
+	tests := []struct {
+		name        string
+		path        string
+		wantStatus  int
+		contains    []string
+		notContains []string
+	}{
+		{
+			name:       "root renders README",
+			path:       "/",
+			wantStatus: http.StatusOK,
+			contains:   []string{`<h1 id="home">Home</h1>`, `href="guide.md"`, server.ReloadPath, "<style>"},
+		},
+		{
+			name:       "markdown file",
+			path:       "/guide.md",
+			wantStatus: http.StatusOK,
+			contains:   []string{"<title>Guide</title>", `<span class="raw">raw</span>`, "&ldquo;quoted&rdquo;"},
+		},
+		{
+			name:       "html path renders markdown sibling",
+			path:       "/guide.html",
+			wantStatus: http.StatusOK,
+			contains:   []string{`<h1 id="guide">Guide</h1>`},
+		},
+		{
+			name:        "static file",
+			path:        "/logo.png",
+			wantStatus:  http.StatusOK,
+			contains:    []string{"PNG"},
+			notContains: []string{server.ReloadPath},
+		},
+		{
+			name:        "directory listing",
+			path:        "/sub/",
+			wantStatus:  http.StatusOK,
+			contains:    []string{"Index of /sub/", `href="../"`, `href="page.markdown"`, `href="nested/"`},
+			notContains: []string{"notes.txt", ".hidden.md"},
+		},
+		{
+			name:       "index.md preferred over README.md",
+			path:       "/index-first/",
+			wantStatus: http.StatusOK,
+			contains:   []string{"<h1 id=\"index\">Index</h1>"},
+		},
+		{
+			name:       "missing file",
+			path:       "/missing.md",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "path traversal",
+			path:       "/../../etc/passwd",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:        "symlink escaping the directory",
+			path:        "/link.md",
+			wantStatus:  http.StatusForbidden,
+			notContains: []string{"Secret"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := get(t, ts.URL+tt.path)
+			if status != tt.wantStatus {
+				t.Errorf("GET %s status = %d, want %d", tt.path, status, tt.wantStatus)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(body, want) {
+					t.Errorf("GET %s body missing %q", tt.path, want)
+				}
+			}
+			for _, unwanted := range tt.notContains {
+				if strings.Contains(body, unwanted) {
+					t.Errorf("GET %s body unexpectedly contains %q", tt.path, unwanted)
+				}
+			}
+		})
+	}
+}
+
+// TestServer_DirectoryRedirect tests that directory URLs get a trailing slash
+func TestServer_DirectoryRedirect(t *testing.T) {
+	_, ts := newTestServer(t, converter.DefaultOptions())
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	resp, err := client.Get(ts.URL + "/sub") //nolint:noctx // test request
+	if err != nil {
+		t.Fatalf("GET error = %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusMovedPermanently {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusMovedPermanently)
+	}
+	if got := resp.Header.Get("Location"); got != "/sub/" {
+		t.Errorf("Location = %q, want %q", got, "/sub/")
+	}
+}
+
+// TestServer_Options tests that converter options such as safe mode apply
+func TestServer_Options(t *testing.T) {
+	opts := converter.Options{SafeMode: true}
+	_, ts := newTestServer(t, opts)
+
+	_, body := get(t, ts.URL+"/guide.md")
+	if strings.Contains(body, `<span class="raw">`) {
+		t.Error("Expected raw HTML to be stripped in safe mode")
+	}
+	if !strings.Contains(body, "&quot;quoted&quot;") {
+		t.Error("Expected straight quotes without typography options")
+	}
+}
+
+// TestServer_MethodNotAllowed tests that only GET and HEAD are served
+func TestServer_MethodNotAllowed(t *testing.T) {
+	srv, _ := newTestServer(t, converter.DefaultOptions())
+
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/guide.md", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusMethodNotAllowed)
+	}
+}
+
+// TestServer_SetConverter tests that a replaced converter is used for later requests
+func TestServer_SetConverter(t *testing.T) {
+	srv, ts := newTestServer(t, converter.DefaultOptions())
+
+	opts := converter.DefaultOptions()
+	opts.AdditionalCSS = ".changed { color: red; }"
+	srv.SetConverter(converter.NewCompleteConverter(opts))
+
+	if _, body := get(t, ts.URL+"/guide.md"); !strings.Contains(body, ".changed { color: red; }") {
+		t.Error("Expected the new converter's CSS in the page")
+	}
+}
+
+// TestServer_Reload tests that Reload sends an event to connected pages
+func TestServer_Reload(t *testing.T) {
+	srv, ts := newTestServer(t, converter.DefaultOptions())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+server.ReloadPath, nil)
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET error = %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if got := resp.Header.Get("Content-Type"); got != "text/event-stream" {
+		t.Errorf("Content-Type = %q, want text/event-stream", got)
+	}
+
+	// The stream is registered before the headers are sent, so the client
+	// is connected once Do has returned.
+	srv.Reload()
+	line, err := bufio.NewReader(resp.Body).ReadString('\n')
+	if err != nil {
+		t.Fatalf("Failed to read event: %v", err)
+	}
+	if line != "data: reload\n" {
+		t.Errorf("event = %q, want %q", line, "data: reload\n")
+	}
+
+	// Closing the server ends the stream.
+	if err := srv.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if _, err := io.ReadAll(resp.Body); err != nil && !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the stream to end, got %v", err)
+	}
+}
+
+// TestNew_MissingDir tests that an unusable directory returns ErrServe
+func TestNew_MissingDir(t *testing.T) {
+	_, err := server.New(filepath.Join(t.TempDir(), "missing"), converter.NewCompleteConverter(converter.DefaultOptions()))
+	if !errors.Is(err, server.ErrServe) {
+		t.Errorf("New() error = %v, want %v", err, server.ErrServe)
+	}
+}
@@ -0,0 +1,87 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severity ranks how serious a lint diagnostic is.
+type Severity int
+
+const (
+	// SeverityInfo marks diagnostics that are purely informational.
+	SeverityInfo Severity = iota
+	// SeverityWarning marks likely problems that do not fail validation.
+	SeverityWarning
+	// SeverityError marks problems that fail validation.
+	SeverityError
+)
+
+// severityNames maps each Severity to its name in configuration and output.
+var severityNames = map[Severity]string{
+	SeverityInfo:    "info",
+	SeverityWarning: "warning",
+	SeverityError:   "error",
+}
+
+// String returns the lower-case name of the severity.
+func (s Severity) String() string {
+	if name, ok := severityNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+// ParseSeverity returns the Severity named by name ("info", "warning" or
+// "error"), ignoring case.
+func ParseSeverity(name string) (Severity, error) {
+	for s, n := range severityNames {
+		if strings.EqualFold(name, n) {
+			return s, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %q (use info, warning or error)", ErrUnknownSeverity, name)
+}
+
+// MarshalText encodes the severity as its name.
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText decodes a severity name.
+func (s *Severity) UnmarshalText(text []byte) error {
+	parsed, err := ParseSeverity(string(text))
+	if err != nil {
+		return err
+	}
+	*s = parsed
+	return nil
+}
+
+// Diagnostic is a single finding reported by a lint rule.
+type Diagnostic struct {
+	// File is the validated file, or empty when content was validated
+	// directly.
+	File string `json:"file,omitempty"`
+	// Line is the 1-based line of the finding in the validated content,
+	// counting front matter lines.
+	Line int `json:"line"`
+	// Column is the 1-based column of the finding, in characters.
+	Column int `json:"column"`
+	// Rule is the ID of the rule that reported the finding.
+	Rule string `json:"rule"`
+	// Severity is the configured severity of the rule.
+	Severity Severity `json:"severity"`
+	// Message describes the finding.
+	Message string `json:"message"`
+}
+
+// String formats the diagnostic as "file:line:column: severity: message
+// (rule)", omitting "file:" when File is empty.
+func (d Diagnostic) String() string {
+	s := fmt.Sprintf("%d:%d: %s: %s (%s)", d.Line, d.Column, d.Severity, d.Message, d.Rule)
+	if d.File != "" {
+		s = d.File + ":" + s
+	}
+	return s
+}
@@ -0,0 +1,18 @@
+package validator
+
+import "errors"
+
+var (
+	// ErrInvalidURLPattern is returned when an external link allow or deny pattern is malformed.
+	ErrInvalidURLPattern = errors.New("invalid URL pattern")
+	// ErrLinkCache is returned when the external link cache cannot be read or written.
+	ErrLinkCache = errors.New("link cache error")
+	// ErrLint is returned when validation reports diagnostics of error severity.
+	ErrLint = errors.New("lint errors found")
+	// ErrUnknownOutputFormat is returned when a diagnostics output format is not recognised.
+	ErrUnknownOutputFormat = errors.New("unknown output format")
+	// ErrUnknownRule is returned when a lint configuration names a rule that does not exist.
+	ErrUnknownRule = errors.New("unknown lint rule")
+	// ErrUnknownSeverity is returned when a severity name is not recognised.
+	ErrUnknownSeverity = errors.New("unknown severity")
+)
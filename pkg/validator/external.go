@@ -0,0 +1,432 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yuin/goldmark/ast"
+)
+
+// Defaults applied by NewExternalChecker to zero ExternalOptions fields.
+const (
+	DefaultExternalConcurrency = 8
+	DefaultExternalTimeout     = 10 * time.Second
+	DefaultExternalCacheTTL    = 24 * time.Hour
+	defaultRetryDelay          = 500 * time.Millisecond
+)
+
+// HTTPDoer sends HTTP requests for an ExternalChecker. *http.Client
+// implements it; tests can inject a client for an httptest.Server.
+type HTTPDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// ExternalOptions configures an ExternalChecker.
+type ExternalOptions struct {
+	// Client sends the requests. It should not follow redirects, or they
+	// cannot be reported. Nil uses an *http.Client that does not follow them.
+	Client HTTPDoer
+
+	// Concurrency is the maximum number of requests in flight. Values below
+	// 1 default to DefaultExternalConcurrency.
+	Concurrency int
+
+	// Timeout bounds each request attempt. Zero defaults to
+	// DefaultExternalTimeout.
+	Timeout time.Duration
+
+	// Retries is the number of times a request is repeated after a network
+	// error, a 429 or a 5xx response.
+	Retries int
+
+	// RetryDelay is the delay before the first retry; it doubles for each
+	// further retry. Zero defaults to 500ms.
+	RetryDelay time.Duration
+
+	// HostInterval is the minimum time between the starts of two requests
+	// to the same host. Zero disables per-host rate limiting.
+	HostInterval time.Duration
+
+	// Allow, when non-empty, restricts checking to the URLs matching one of
+	// its patterns. Deny lists patterns of URLs that are never checked and
+	// wins over Allow. A pattern containing "://" matches URLs it prefixes;
+	// any other pattern is a path.Match glob for the host, e.g.
+	// "*.example.com".
+	Allow []string
+	Deny  []string
+
+	// CacheFile is where results are kept between runs. Empty disables the
+	// disk cache.
+	CacheFile string
+
+	// CacheTTL is how long cached results are reused. Zero defaults to
+	// DefaultExternalCacheTTL.
+	CacheTTL time.Duration
+}
+
+// LinkResult is the outcome of checking one external URL.
+type LinkResult struct {
+	// StatusCode is the HTTP status of the final attempt, or 0 when no
+	// response was received.
+	StatusCode int `json:"status"`
+	// Location is the redirect target of a 3xx response.
+	Location string `json:"location,omitempty"`
+	// Error describes why no response was received.
+	Error string `json:"error,omitempty"`
+	// Checked is when the URL was checked.
+	Checked time.Time `json:"checked"`
+}
+
+// ExternalChecker checks absolute http and https URLs for the external-link
+// and external-redirect rules. It is safe for concurrent use: every URL is
+// requested at most once, and concurrency and per-host rate limits apply
+// across all callers. Set it as LintConfig.External to enable the rules.
+type ExternalChecker struct {
+	opts   ExternalOptions
+	client HTTPDoer
+	sem    chan struct{}
+	cache  *linkCache
+
+	mu        sync.Mutex
+	inflight  map[string]*linkCheck
+	hostSlots map[string]time.Time
+}
+
+// linkCheck is a check of one URL that other callers can wait for.
+type linkCheck struct {
+	done   chan struct{}
+	result LinkResult
+}
+
+// NewExternalChecker returns an ExternalChecker with opts, loading the disk
+// cache when opts.CacheFile is set. A missing cache file is not an error.
+func NewExternalChecker(opts ExternalOptions) (*ExternalChecker, error) {
+	if opts.Concurrency < 1 {
+		opts.Concurrency = DefaultExternalConcurrency
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultExternalTimeout
+	}
+	if opts.RetryDelay <= 0 {
+		opts.RetryDelay = defaultRetryDelay
+	}
+	if opts.CacheTTL <= 0 {
+		opts.CacheTTL = DefaultExternalCacheTTL
+	}
+	for _, p := range slices.Concat(opts.Allow, opts.Deny) {
+		if _, err := path.Match(p, ""); err != nil && !strings.Contains(p, "://") {
+			return nil, fmt.Errorf("%w: %q: %w", ErrInvalidURLPattern, p, err)
+		}
+	}
+
+	client := opts.Client
+	if client == nil {
+		client = &http.Client{
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		}
+	}
+	cache, err := loadLinkCache(opts.CacheFile, opts.CacheTTL)
+	if err != nil {
+		return nil, err
+	}
+	return &ExternalChecker{
+		opts:      opts,
+		client:    client,
+		sem:       make(chan struct{}, opts.Concurrency),
+		cache:     cache,
+		inflight:  make(map[string]*linkCheck),
+		hostSlots: make(map[string]time.Time),
+	}, nil
+}
+
+// Checks reports whether rawURL is an http or https URL selected by the
+// allow and deny lists.
+func (c *ExternalChecker) Checks(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return false
+	}
+	if matchesURLPattern(c.opts.Deny, u) {
+		return false
+	}
+	return len(c.opts.Allow) == 0 || matchesURLPattern(c.opts.Allow, u)
+}
+
+// Check returns the result for rawURL, from the cache when it holds a fresh
+// entry. The fragment is not part of the request.
+func (c *ExternalChecker) Check(ctx context.Context, rawURL string) LinkResult {
+	key, _, _ := strings.Cut(rawURL, "#")
+
+	c.mu.Lock()
+	if lc, ok := c.inflight[key]; ok {
+		c.mu.Unlock()
+		select {
+		case <-lc.done:
+			return lc.result
+		case <-ctx.Done():
+			return LinkResult{Error: ctx.Err().Error(), Checked: time.Now()}
+		}
+	}
+	lc := &linkCheck{done: make(chan struct{})}
+	c.inflight[key] = lc
+	c.mu.Unlock()
+
+	if res, ok := c.cache.get(key); ok {
+		lc.result = res
+	} else {
+		lc.result = c.request(ctx, key)
+		// Failures that may be transient are checked again next run.
+		if !retryable(lc.result) {
+			c.cache.put(key, lc.result)
+		}
+	}
+	close(lc.done)
+	return lc.result
+}
+
+// SaveCache writes the results to the disk cache, if one is configured.
+func (c *ExternalChecker) SaveCache() error {
+	return c.cache.save()
+}
+
+// request checks rawURL with retries.
+func (c *ExternalChecker) request(ctx context.Context, rawURL string) LinkResult {
+	delay := c.opts.RetryDelay
+	var res LinkResult
+	for attempt := 0; ; attempt++ {
+		res = c.attempt(ctx, rawURL)
+		if attempt >= c.opts.Retries || !retryable(res) {
+			return res
+		}
+		if err := sleepContext(ctx, delay); err != nil {
+			return res
+		}
+		delay *= 2
+	}
+}
+
+// attempt sends a HEAD request for rawURL, falling back to GET when the
+// server rejects HEAD.
+func (c *ExternalChecker) attempt(ctx context.Context, rawURL string) LinkResult {
+	res := c.do(ctx, http.MethodHead, rawURL)
+	// Some servers refuse HEAD outright, or only for unknown clients.
+	switch res.StatusCode {
+	case http.StatusMethodNotAllowed, http.StatusNotImplemented, http.StatusForbidden:
+		res = c.do(ctx, http.MethodGet, rawURL)
+	}
+	return res
+}
+
+// do sends one request once the host's rate limit and a concurrency slot
+// allow it.
+func (c *ExternalChecker) do(ctx context.Context, method, rawURL string) LinkResult {
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, nil)
+	if err != nil {
+		return LinkResult{Error: err.Error(), Checked: time.Now()}
+	}
+	if err := sleepContext(ctx, c.reserveHost(req.URL.Host)); err != nil {
+		return LinkResult{Error: err.Error(), Checked: time.Now()}
+	}
+	select {
+	case c.sem <- struct{}{}:
+	case <-ctx.Done():
+		return LinkResult{Error: ctx.Err().Error(), Checked: time.Now()}
+	}
+	defer func() { <-c.sem }()
+
+	reqCtx, cancel := context.WithTimeout(ctx, c.opts.Timeout)
+	defer cancel()
+	req = req.WithContext(reqCtx)
+	req.Header.Set("User-Agent", "mdtohtml-link-checker")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return LinkResult{Error: requestError(err), Checked: time.Now()}
+	}
+	defer func() { _ = resp.Body.Close() }()
+	// Drain a little of the body so the connection can be reused.
+	const drainLimit = 64 << 10
+	_, _ = io.CopyN(io.Discard, resp.Body, drainLimit)
+
+	res := LinkResult{StatusCode: resp.StatusCode, Checked: time.Now()}
+	if isRedirect(resp.StatusCode) {
+		if loc, err := resp.Location(); err == nil {
+			res.Location = loc.String()
+		}
+	}
+	return res
+}
+
+// reserveHost claims the next request slot for host and returns how long
+// to wait for it.
+func (c *ExternalChecker) reserveHost(host string) time.Duration {
+	if c.opts.HostInterval <= 0 {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	slot := c.hostSlots[host]
+	if slot.Before(now) {
+		slot = now
+	}
+	c.hostSlots[host] = slot.Add(c.opts.HostInterval)
+	return slot.Sub(now)
+}
+
+// retryable reports whether res may succeed when the request is repeated.
+func retryable(res LinkResult) bool {
+	return res.StatusCode == 0 || res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= http.StatusInternalServerError
+}
+
+// isRedirect reports whether status is a redirect with a Location.
+func isRedirect(status int) bool {
+	switch status {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return true
+	}
+	return false
+}
+
+// requestError describes a failed request without repeating the URL, which
+// diagnostics already name.
+func requestError(err error) string {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "timed out"
+	}
+	return err.Error()
+}
+
+// matchesURLPattern reports whether u matches one of patterns; see
+// ExternalOptions.Allow.
+func matchesURLPattern(patterns []string, u *url.URL) bool {
+	for _, p := range patterns {
+		if strings.Contains(p, "://") {
+			if strings.HasPrefix(u.String(), p) {
+				return true
+			}
+			continue
+		}
+		if ok, _ := path.Match(strings.ToLower(p), strings.ToLower(u.Hostname())); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// sleepContext waits for d or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err() //nolint:wrapcheck // reported as a link result
+	}
+}
+
+// externalLink is an absolute URL found in a document.
+type externalLink struct {
+	pos int
+	url string
+}
+
+// externalLinks returns the http and https URLs of the links, images and
+// autolinks in d that the checker selects.
+func externalLinks(d *lintDocument) []externalLink {
+	var links []externalLink
+	walkEntering(d.doc, func(n ast.Node) {
+		var dest string
+		switch n := n.(type) {
+		case *ast.Link:
+			dest = string(n.Destination)
+		case *ast.Image:
+			dest = string(n.Destination)
+		case *ast.AutoLink:
+			if n.AutoLinkType != ast.AutoLinkURL {
+				return
+			}
+			dest = string(n.URL(d.source))
+			// Linkified "www." URLs have no scheme.
+			if strings.HasPrefix(dest, "www.") {
+				dest = "http://" + dest
+			}
+		default:
+			return
+		}
+		if dest = strings.TrimSpace(dest); d.external.Checks(dest) {
+			links = append(links, externalLink{pos: n.Pos(), url: dest})
+		}
+	})
+	return links
+}
+
+// checkExternalLinks requests the external URLs of d concurrently and
+// returns their results in document order.
+func checkExternalLinks(d *lintDocument) ([]externalLink, []LinkResult) {
+	if d.externalResults != nil || d.external == nil {
+		return d.externalLinks, d.externalResults
+	}
+	links := externalLinks(d)
+	results := make([]LinkResult, len(links))
+	var wg sync.WaitGroup
+	for i, l := range links {
+		wg.Go(func() {
+			results[i] = d.external.Check(context.Background(), l.url)
+		})
+	}
+	wg.Wait()
+	d.externalLinks, d.externalResults = links, results
+	return links, results
+}
+
+// checkExternalLink reports external URLs that fail or answer with a 4xx or
+// 5xx status.
+func checkExternalLink(d *lintDocument, report reportFunc) {
+	links, results := checkExternalLinks(d)
+	for i, res := range results {
+		switch {
+		case res.Error != "":
+			report(links[i].pos, "external link %q could not be checked: %s", links[i].url, res.Error)
+		case res.StatusCode >= http.StatusBadRequest:
+			report(links[i].pos, "external link %q returned %s", links[i].url, statusText(res.StatusCode))
+		}
+	}
+}
+
+// checkExternalRedirect reports external URLs that redirect.
+func checkExternalRedirect(d *lintDocument, report reportFunc) {
+	links, results := checkExternalLinks(d)
+	for i, res := range results {
+		if isRedirect(res.StatusCode) {
+			report(links[i].pos, "external link %q redirects (%d) to %q", links[i].url, res.StatusCode, res.Location)
+		}
+	}
+}
+
+// statusText formats an HTTP status as "404 Not Found".
+func statusText(code int) string {
+	if text := http.StatusText(code); text != "" {
+		return strconv.Itoa(code) + " " + text
+	}
+	return strconv.Itoa(code)
+}
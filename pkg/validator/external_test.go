@@ -0,0 +1,300 @@
+package validator_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sgaunet/mdtohtml/pkg/validator"
+)
+
+// linkServer starts a server for external link tests and returns it with a
+// client that does not follow redirects and a per-path request counter.
+func linkServer(t *testing.T) (*httptest.Server, *http.Client, *hitCounter) {
+	t.Helper()
+	hits := &hitCounter{counts: make(map[string]int)}
+	var flaky atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok", func(http.ResponseWriter, *http.Request) {})
+	mux.HandleFunc("/missing", http.NotFound)
+	mux.HandleFunc("/broken", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	mux.HandleFunc("/moved", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/ok", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/get-only", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	// flaky fails twice before succeeding.
+	mux.HandleFunc("/flaky", func(w http.ResponseWriter, _ *http.Request) {
+		if flaky.Add(1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.add(r.Method + " " + r.URL.Path)
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	client := srv.Client()
+	client.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+	return srv, client, hits
+}
+
+// hitCounter counts requests by method and path.
+type hitCounter struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func (h *hitCounter) add(key string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.counts[key]++
+}
+
+func (h *hitCounter) get(key string) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.counts[key]
+}
+
+// TestLint_External tests the external-link and external-redirect rules against a local server
+func TestLint_External(t *testing.T) {
+	srv, client, hits := linkServer(t)
+	checker, err := validator.NewExternalChecker(validator.ExternalOptions{Client: client, RetryDelay: time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewExternalChecker() error = %v", err)
+	}
+
+	input := fmt.Sprintf("# Links\n\n[ok](%[1]s/ok)\n[missing](%[1]s/missing#part)\n![broken](%[1]s/broken)\n"+
+		"<%[1]s/moved>\n[get](%[1]s/get-only)\n[again](%[1]s/missing)\n"+
+		"[local](other.md) [mail](mailto:someone@example.com)\n", srv.URL)
+	diags := validator.Lint([]byte(input), validator.LintConfig{External: checker})
+
+	var got []string
+	for _, d := range diags {
+		got = append(got, fmt.Sprintf("%s@%d:%d", d.Rule, d.Line, d.Column))
+	}
+	want := []string{
+		"external-link@4:1", "external-link@5:1", "external-redirect@6:1", "external-link@8:1",
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("Lint() = %v, want %v", got, want)
+	}
+
+	wantMessages := []string{
+		fmt.Sprintf("external link %q returned 404 Not Found", srv.URL+"/missing#part"),
+		fmt.Sprintf("external link %q returned 500 Internal Server Error", srv.URL+"/broken"),
+		fmt.Sprintf("external link %q redirects (301) to %q", srv.URL+"/moved", srv.URL+"/ok"),
+	}
+	for i, msg := range wantMessages {
+		if diags[i].Message != msg {
+			t.Errorf("diagnostic %d message = %q, want %q", i, diags[i].Message, msg)
+		}
+	}
+	if diags[0].Severity != validator.SeverityError || diags[2].Severity != validator.SeverityWarning {
+		t.Errorf("unexpected severities: %v, %v", diags[0].Severity, diags[2].Severity)
+	}
+
+	// Each URL is requested once, whatever its fragment or number of links.
+	if n := hits.get("HEAD /missing"); n != 1 {
+		t.Errorf("HEAD /missing requested %d times, want 1", n)
+	}
+	if n := hits.get("GET /get-only"); n != 1 {
+		t.Errorf("GET /get-only requested %d times after HEAD was refused, want 1", n)
+	}
+}
+
+// TestLint_ExternalDisabled tests that external rules report nothing without a checker
+func TestLint_ExternalDisabled(t *testing.T) {
+	srv, _, hits := linkServer(t)
+	diags := validator.Lint([]byte("[missing]("+srv.URL+"/missing)\n"), validator.LintConfig{})
+	if len(diags) != 0 {
+		t.Errorf("Lint() = %v, want no diagnostics", diags)
+	}
+	if n := hits.get("HEAD /missing"); n != 0 {
+		t.Errorf("server received %d requests, want none", n)
+	}
+}
+
+// TestExternalChecker_Retries tests that 5xx responses are retried
+func TestExternalChecker_Retries(t *testing.T) {
+	tests := []struct {
+		name    string
+		retries int
+		want    int
+	}{
+		{name: "no retries", retries: 0, want: http.StatusServiceUnavailable},
+		{name: "too few retries", retries: 1, want: http.StatusServiceUnavailable},
+		{name: "enough retries", retries: 2, want: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, client, hits := linkServer(t)
+			checker, err := validator.NewExternalChecker(validator.ExternalOptions{
+				Client:     client,
+				Retries:    tt.retries,
+				RetryDelay: time.Millisecond,
+			})
+			if err != nil {
+				t.Fatalf("NewExternalChecker() error = %v", err)
+			}
+			res := checker.Check(context.Background(), srv.URL+"/flaky")
+			if res.StatusCode != tt.want {
+				t.Errorf("Check() status = %d, want %d", res.StatusCode, tt.want)
+			}
+			if n := hits.get("HEAD /flaky"); n != tt.retries+1 && tt.want != http.StatusOK {
+				t.Errorf("requested %d times, want %d", n, tt.retries+1)
+			}
+		})
+	}
+}
+
+// TestExternalChecker_Unreachable tests reporting of URLs that give no response
+func TestExternalChecker_Unreachable(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	url := srv.URL + "/gone"
+	srv.Close()
+
+	checker, err := validator.NewExternalChecker(validator.ExternalOptions{Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("NewExternalChecker() error = %v", err)
+	}
+	diags := validator.Lint([]byte("[gone]("+url+")\n"), validator.LintConfig{External: checker})
+	if len(diags) != 1 || diags[0].Rule != validator.RuleExternalLink {
+		t.Fatalf("Lint() = %v, want one external-link diagnostic", diags)
+	}
+	if !strings.Contains(diags[0].Message, "could not be checked") {
+		t.Errorf("message = %q, want it to say the link could not be checked", diags[0].Message)
+	}
+}
+
+// TestExternalChecker_Checks tests URL selection by scheme and allow/deny lists
+func TestExternalChecker_Checks(t *testing.T) {
+	tests := []struct {
+		name  string
+		allow []string
+		deny  []string
+		url   string
+		want  bool
+	}{
+		{name: "http", url: "http://example.com/a", want: true},
+		{name: "https", url: "https://example.com/a", want: true},
+		{name: "mailto", url: "mailto:a@example.com", want: false},
+		{name: "relative", url: "docs/a.md", want: false},
+		{name: "denied host", deny: []string{"example.com"}, url: "https://EXAMPLE.com/a", want: false},
+		{name: "denied subdomain glob", deny: []string{"*.example.com"}, url: "https://www.example.com/", want: false},
+		{name: "glob does not match apex", deny: []string{"*.example.com"}, url: "https://example.com/", want: true},
+		{name: "denied prefix", deny: []string{"https://example.com/private/"}, url: "https://example.com/private/x", want: false},
+		{name: "prefix leaves other paths", deny: []string{"https://example.com/private/"}, url: "https://example.com/public", want: true},
+		{name: "allowed host", allow: []string{"example.com"}, url: "https://example.com/", want: true},
+		{name: "host not allowed", allow: []string{"example.com"}, url: "https://example.org/", want: false},
+		{name: "deny wins over allow", allow: []string{"*"}, deny: []string{"localhost"}, url: "http://localhost:8080/", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker, err := validator.NewExternalChecker(validator.ExternalOptions{Allow: tt.allow, Deny: tt.deny})
+			if err != nil {
+				t.Fatalf("NewExternalChecker() error = %v", err)
+			}
+			if got := checker.Checks(tt.url); got != tt.want {
+				t.Errorf("Checks(%q) = %v, want %v", tt.url, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := validator.NewExternalChecker(validator.ExternalOptions{Deny: []string{"[bad"}}); !errors.Is(err, validator.ErrInvalidURLPattern) {
+		t.Errorf("NewExternalChecker() with a malformed pattern error = %v, want ErrInvalidURLPattern", err)
+	}
+}
+
+// TestExternalChecker_Cache tests that results are reused across checkers through the cache file
+func TestExternalChecker_Cache(t *testing.T) {
+	srv, client, hits := linkServer(t)
+	cacheFile := filepath.Join(t.TempDir(), "cache", "links.json")
+	newChecker := func(ttl time.Duration) *validator.ExternalChecker {
+		t.Helper()
+		checker, err := validator.NewExternalChecker(validator.ExternalOptions{
+			Client:    client,
+			CacheFile: cacheFile,
+			CacheTTL:  ttl,
+		})
+		if err != nil {
+			t.Fatalf("NewExternalChecker() error = %v", err)
+		}
+		return checker
+	}
+	check := func(checker *validator.ExternalChecker) {
+		t.Helper()
+		for _, path := range []string{"/missing", "/broken"} {
+			checker.Check(context.Background(), srv.URL+path)
+		}
+		if err := checker.SaveCache(); err != nil {
+			t.Fatalf("SaveCache() error = %v", err)
+		}
+	}
+
+	check(newChecker(time.Hour))
+	if _, err := os.Stat(cacheFile); err != nil {
+		t.Fatalf("cache file not written: %v", err)
+	}
+	check(newChecker(time.Hour))
+	if n := hits.get("HEAD /missing"); n != 1 {
+		t.Errorf("cached URL requested %d times, want 1", n)
+	}
+	// Server errors may be transient and are not cached.
+	if n := hits.get("HEAD /broken"); n != 2 {
+		t.Errorf("failing URL requested %d times, want 2", n)
+	}
+
+	check(newChecker(time.Nanosecond))
+	if n := hits.get("HEAD /missing"); n != 2 {
+		t.Errorf("expired URL requested %d times in total, want 2", n)
+	}
+
+	if err := os.WriteFile(cacheFile, []byte("not json"), 0644); err != nil {
+		t.Fatalf("Failed to write cache file: %v", err)
+	}
+	if _, err := validator.NewExternalChecker(validator.ExternalOptions{CacheFile: cacheFile}); !errors.Is(err, validator.ErrLinkCache) {
+		t.Errorf("NewExternalChecker() with a corrupt cache error = %v, want ErrLinkCache", err)
+	}
+}
+
+// TestExternalChecker_HostInterval tests per-host rate limiting
+func TestExternalChecker_HostInterval(t *testing.T) {
+	srv, client, _ := linkServer(t)
+	const interval = 50 * time.Millisecond
+	checker, err := validator.NewExternalChecker(validator.ExternalOptions{Client: client, HostInterval: interval})
+	if err != nil {
+		t.Fatalf("NewExternalChecker() error = %v", err)
+	}
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := range 3 {
+		wg.Go(func() {
+			checker.Check(context.Background(), fmt.Sprintf("%s/ok?n=%d", srv.URL, i))
+		})
+	}
+	wg.Wait()
+	if elapsed := time.Since(start); elapsed < 2*interval {
+		t.Errorf("3 requests to one host took %v, want at least %v", elapsed, 2*interval)
+	}
+}
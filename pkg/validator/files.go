@@ -0,0 +1,45 @@
+package validator
+
+import (
+	"runtime"
+	"sync"
+)
+
+// FileResult is the outcome of validating one file with ValidateFiles.
+type FileResult struct {
+	// Path is the validated file.
+	Path string
+	// Diagnostics are the findings for the file, with File set to Path.
+	Diagnostics []Diagnostic
+	// Err is non-nil when the file could not be validated at all, e.g.
+	// because it could not be read.
+	Err error
+}
+
+// ValidateFiles validates files with up to concurrency workers sharing v,
+// which must be safe for concurrent use; GoldmarkValidator is. Values of
+// concurrency below 1 default to runtime.GOMAXPROCS(0). The results are
+// returned in the order of files.
+func ValidateFiles(v Validator, files []string, concurrency int) []FileResult {
+	if concurrency < 1 {
+		concurrency = runtime.GOMAXPROCS(0)
+	}
+	results := make([]FileResult, len(files))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for range min(concurrency, len(files)) {
+		wg.Go(func() {
+			for i := range jobs {
+				diags, err := v.ValidateFile(files[i])
+				results[i] = FileResult{Path: files[i], Diagnostics: diags, Err: err}
+			}
+		})
+	}
+	for i := range files {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,61 @@
+package validator_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sgaunet/mdtohtml/pkg/converter"
+	"github.com/sgaunet/mdtohtml/pkg/validator"
+)
+
+// TestValidateFiles tests concurrent validation of several files
+func TestValidateFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	contents := map[string]string{
+		"clean.md":   "# Clean\n\nNothing to report.\n",
+		"broken.md":  "# Broken\n\n[text][missing]\n",
+		"warning.md": "# One\n\n# Two\n",
+	}
+	var files []string
+	for _, name := range []string{"clean.md", "broken.md", "missing.md", "warning.md"} {
+		path := filepath.Join(tmpDir, name)
+		if content, ok := contents[name]; ok {
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatalf("Failed to create test file: %v", err)
+			}
+		}
+		files = append(files, path)
+	}
+
+	val := validator.NewGoldmarkValidator(converter.NewGoldmarkConverter(converter.DefaultOptions()))
+
+	for _, concurrency := range []int{0, 1, 3, 16} {
+		results := validator.ValidateFiles(val, files, concurrency)
+		if len(results) != len(files) {
+			t.Fatalf("concurrency %d: got %d results, want %d", concurrency, len(results), len(files))
+		}
+		for i, r := range results {
+			if r.Path != files[i] {
+				t.Errorf("concurrency %d: result %d is for %s, want %s", concurrency, i, r.Path, files[i])
+			}
+		}
+		if r := results[0]; r.Err != nil || len(r.Diagnostics) != 0 {
+			t.Errorf("clean.md: got %+v, want no findings", r)
+		}
+		if r := results[1]; r.Err != nil || validator.CountSeverity(r.Diagnostics, validator.SeverityError) != 1 {
+			t.Errorf("broken.md: got %+v, want one error", r)
+		}
+		if r := results[2]; r.Err == nil {
+			t.Error("missing.md: expected a read error")
+		}
+		if r := results[3]; r.Err != nil || validator.CountSeverity(r.Diagnostics, validator.SeverityWarning) != 1 ||
+			r.Diagnostics[0].File != files[3] {
+			t.Errorf("warning.md: got %+v, want one warning", r)
+		}
+	}
+
+	if results := validator.ValidateFiles(val, nil, 4); len(results) != 0 {
+		t.Errorf("ValidateFiles(nil) = %+v, want no results", results)
+	}
+}
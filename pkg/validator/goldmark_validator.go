@@ -3,6 +3,7 @@ package validator
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/sgaunet/mdtohtml/pkg/converter"
 )
@@ -10,33 +11,82 @@ import (
 // GoldmarkValidator implements Validator using the Goldmark library.
 type GoldmarkValidator struct {
 	converter converter.Converter
+	lint      LintConfig
 }
 
 // NewGoldmarkValidator creates a new validator using the given converter.
+// It runs every lint rule with its default severity.
 func NewGoldmarkValidator(conv converter.Converter) *GoldmarkValidator {
 	return &GoldmarkValidator{
 		converter: conv,
 	}
 }
 
-// Validate validates markdown content by attempting to convert it.
-func (v *GoldmarkValidator) Validate(content []byte) error {
-	_, err := v.converter.Convert(content)
+// NewGoldmarkValidatorWithLint creates a new validator using the given
+// converter and lint configuration. It returns an error wrapping
+// ErrUnknownRule when the configuration names a rule that does not exist.
+func NewGoldmarkValidatorWithLint(conv converter.Converter, config LintConfig) (*GoldmarkValidator, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &GoldmarkValidator{
+		converter: conv,
+		lint:      config,
+	}, nil
+}
+
+// Validate validates markdown content by attempting to convert it and
+// running the lint rules. The error is non-nil only when conversion fails;
+// lint findings, including those of error severity, are returned as
+// diagnostics. Relative links and images are only checked by ValidateFile.
+func (v *GoldmarkValidator) Validate(content []byte) ([]Diagnostic, error) {
+	return v.validate(content, "")
+}
+
+// ValidateFile validates a markdown file by reading and validating its
+// content. Relative links and images are resolved against the file's
+// directory. The diagnostics have File set to path.
+func (v *GoldmarkValidator) ValidateFile(path string) ([]Diagnostic, error) {
+	content, err := readFile(path)
 	if err != nil {
-		return fmt.Errorf("validation failed: %w", err)
+		return nil, err
+	}
+
+	diags, err := v.validate(content, filepath.Dir(path))
+	for i := range diags {
+		diags[i].File = path
 	}
-	return nil
+	return diags, err
 }
 
-// ValidateFile validates a markdown file by reading and validating its content.
-func (v *GoldmarkValidator) ValidateFile(path string) error {
+// validate converts and lints content read from a file in dir, or from no
+// file when dir is empty.
+func (v *GoldmarkValidator) validate(content []byte, dir string) ([]Diagnostic, error) {
+	if _, err := v.converter.Convert(content); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	return lint(content, dir, v.lint), nil
+}
+
+// CountSeverity returns the number of diagnostics with severity s.
+func CountSeverity(diags []Diagnostic, s Severity) int {
+	n := 0
+	for _, d := range diags {
+		if d.Severity == s {
+			n++
+		}
+	}
+	return n
+}
+
+// readFile reads the file at path for validation.
+func readFile(path string) ([]byte, error) {
 	content, err := os.ReadFile(path)
 	if err != nil {
 		if os.IsPermission(err) {
-			return fmt.Errorf("permission denied reading file '%s': %w", path, err)
+			return nil, fmt.Errorf("permission denied reading file '%s': %w", path, err)
 		}
-		return fmt.Errorf("error reading file '%s': %w", path, err)
+		return nil, fmt.Errorf("error reading file '%s': %w", path, err)
 	}
-
-	return v.Validate(content)
-}
\ No newline at end of file
+	return content, nil
+}
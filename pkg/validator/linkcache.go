@@ -0,0 +1,129 @@
+package validator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// linkCacheVersion is the format version of the cache file; files of another
+// version are ignored.
+const linkCacheVersion = 1
+
+// linkCacheFile is the JSON layout of the external link cache.
+type linkCacheFile struct {
+	Version int                   `json:"version"`
+	Links   map[string]LinkResult `json:"links"`
+}
+
+// linkCache holds external link results between runs. A cache without a
+// file keeps nothing.
+type linkCache struct {
+	file string
+	ttl  time.Duration
+
+	mu      sync.Mutex
+	links   map[string]LinkResult
+	changed bool
+}
+
+// loadLinkCache reads the cache in file, dropping entries older than ttl. A
+// missing file gives an empty cache.
+func loadLinkCache(file string, ttl time.Duration) (*linkCache, error) {
+	c := &linkCache{file: file, ttl: ttl, links: make(map[string]LinkResult)}
+	if file == "" {
+		return c, nil
+	}
+	data, err := os.ReadFile(file) //nolint:gosec // the cache path is chosen by the user
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrLinkCache, err)
+	}
+	var stored linkCacheFile
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrLinkCache, file, err)
+	}
+	if stored.Version != linkCacheVersion {
+		return c, nil
+	}
+	for u, res := range stored.Links {
+		if c.fresh(res) {
+			c.links[u] = res
+		}
+	}
+	return c, nil
+}
+
+// fresh reports whether res is recent enough to be reused.
+func (c *linkCache) fresh(res LinkResult) bool {
+	return time.Since(res.Checked) < c.ttl
+}
+
+// get returns the cached result for rawURL if it is still fresh.
+func (c *linkCache) get(rawURL string) (LinkResult, bool) {
+	if c.file == "" {
+		return LinkResult{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	res, ok := c.links[rawURL]
+	return res, ok && c.fresh(res)
+}
+
+// put stores the result for rawURL.
+func (c *linkCache) put(rawURL string, res LinkResult) {
+	if c.file == "" {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.links[rawURL] = res
+	c.changed = true
+}
+
+// save writes the cache file if any result was added since it was loaded.
+// The file is replaced atomically, so an interrupted run leaves the
+// previous cache intact.
+func (c *linkCache) save() error {
+	if c.file == "" {
+		return nil
+	}
+	c.mu.Lock()
+	if !c.changed {
+		c.mu.Unlock()
+		return nil
+	}
+	stored := linkCacheFile{Version: linkCacheVersion, Links: maps.Clone(c.links)}
+	c.mu.Unlock()
+
+	data, err := json.MarshalIndent(stored, "", "  ")
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrLinkCache, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.file), 0o750); err != nil {
+		return fmt.Errorf("%w: %w", ErrLinkCache, err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(c.file), filepath.Base(c.file)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrLinkCache, err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("%w: %w", ErrLinkCache, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("%w: %w", ErrLinkCache, err)
+	}
+	if err := os.Rename(tmp.Name(), c.file); err != nil {
+		return fmt.Errorf("%w: %w", ErrLinkCache, err)
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package validator
+
+import (
+	"bytes"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+	"golang.org/x/net/html"
+
+	"github.com/sgaunet/mdtohtml/pkg/frontmatter"
+)
+
+// markdownExts are the extensions of link targets whose heading IDs are
+// checked for fragments.
+var markdownExts = []string{".md", ".markdown"}
+
+// checkBrokenLink reports relative links whose target file does not exist
+// and fragments that match no heading ID of the target document. Fragments
+// of links to the document itself are checked even when it is not a file.
+func checkBrokenLink(d *lintDocument, report reportFunc) {
+	walkEntering(d.doc, func(n ast.Node) {
+		link, ok := n.(*ast.Link)
+		if !ok {
+			return
+		}
+		u, ok := relativeURL(string(link.Destination))
+		if !ok {
+			return
+		}
+		if u.Path == "" {
+			if u.Fragment != "" && !d.ids()[u.Fragment] {
+				report(link.Pos(), "no heading with ID %q in this document", u.Fragment)
+			}
+			return
+		}
+		if d.dir == "" {
+			return
+		}
+		target := filepath.Join(d.dir, filepath.FromSlash(u.Path))
+		info, err := os.Stat(target)
+		if err != nil {
+			report(link.Pos(), "link target %q does not exist", u.Path)
+			return
+		}
+		if u.Fragment == "" || info.IsDir() || !isMarkdownFile(target) {
+			return
+		}
+		if ids, ok := d.targetIDs(target); ok && !ids[u.Fragment] {
+			report(link.Pos(), "no heading with ID %q in %q", u.Fragment, u.Path)
+		}
+	})
+}
+
+// checkMissingImage reports images whose relative source does not exist.
+func checkMissingImage(d *lintDocument, report reportFunc) {
+	if d.dir == "" {
+		return
+	}
+	walkEntering(d.doc, func(n ast.Node) {
+		img, ok := n.(*ast.Image)
+		if !ok {
+			return
+		}
+		u, ok := relativeURL(string(img.Destination))
+		if !ok || u.Path == "" {
+			return
+		}
+		if _, err := os.Stat(filepath.Join(d.dir, filepath.FromSlash(u.Path))); err != nil {
+			report(img.Pos(), "image %q does not exist", u.Path)
+		}
+	})
+}
+
+// relativeURL parses dest and reports whether it is a relative reference
+// that can be resolved against the document's directory. URLs with a scheme
+// or host and root-relative paths, whose base is unknown offline, are not.
+func relativeURL(dest string) (*url.URL, bool) {
+	u, err := url.Parse(strings.TrimSpace(dest))
+	if err != nil || u.Scheme != "" || u.Host != "" || strings.HasPrefix(u.Path, "/") {
+		return nil, false
+	}
+	if u.Path == "" && u.Fragment == "" {
+		return nil, false
+	}
+	return u, true
+}
+
+// isMarkdownFile reports whether path has a Markdown file extension.
+func isMarkdownFile(path string) bool {
+	return slices.ContainsFunc(markdownExts, func(ext string) bool {
+		return strings.EqualFold(filepath.Ext(path), ext)
+	})
+}
+
+// ids returns the heading and HTML element IDs of the linted document.
+func (d *lintDocument) ids() map[string]bool {
+	if d.docIDs == nil {
+		d.docIDs = documentIDs(d.doc, d.source)
+	}
+	return d.docIDs
+}
+
+// targetIDs returns the IDs of the Markdown file at path, parsing it at most
+// once per lint run. It reports false when the file cannot be read.
+func (d *lintDocument) targetIDs(path string) (map[string]bool, bool) {
+	if ids, ok := d.targets[path]; ok {
+		return ids, ids != nil
+	}
+	if d.targets == nil {
+		d.targets = make(map[string]map[string]bool)
+	}
+	content, err := os.ReadFile(path) //nolint:gosec // path is a link target next to the validated file
+	if err != nil {
+		d.targets[path] = nil
+		return nil, false
+	}
+	body := content
+	if _, b, err := frontmatter.Split(content); err == nil {
+		body = b
+	}
+	ids := documentIDs(lintParser.Parse(text.NewReader(body)), body)
+	d.targets[path] = ids
+	return ids, true
+}
+
+// documentIDs returns the IDs a fragment can point at in a parsed document:
+// the heading IDs generated as by parser.WithAutoHeadingID, which the
+// converter uses, and the id and name attributes of raw HTML elements.
+func documentIDs(doc ast.Node, source []byte) map[string]bool {
+	ids := make(map[string]bool)
+	walkEntering(doc, func(n ast.Node) {
+		switch n := n.(type) {
+		case *ast.Heading:
+			if id, ok := n.AttributeString("id"); ok {
+				if b, ok := id.([]byte); ok {
+					ids[string(b)] = true
+				}
+			}
+		case *ast.RawHTML:
+			htmlIDs(n.Segments.Value(source), ids)
+		case *ast.HTMLBlock:
+			htmlIDs(n.Lines().Value(source), ids)
+		}
+	})
+	return ids
+}
+
+// htmlIDs adds the id and name attributes of the tags in fragment to ids.
+func htmlIDs(fragment []byte, ids map[string]bool) {
+	z := html.NewTokenizer(bytes.NewReader(fragment))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return
+		case html.StartTagToken, html.SelfClosingTagToken:
+			for _, a := range z.Token().Attr {
+				if a.Key == "id" || a.Key == "name" {
+					ids[a.Val] = true
+				}
+			}
+		default:
+		}
+	}
+}
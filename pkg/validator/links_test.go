@@ -0,0 +1,109 @@
+package validator_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/sgaunet/mdtohtml/pkg/converter"
+	"github.com/sgaunet/mdtohtml/pkg/validator"
+)
+
+// TestGoldmarkValidator_ValidateFile_Links tests detection of broken relative links and missing images
+func TestGoldmarkValidator_ValidateFile_Links(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"docs/guide.md":    "---\ntitle: Guide\n---\n# Guide\n\n## Install Steps\n\n## Install Steps\n\n<span id=\"legacy\"></span>\n",
+		"docs/img/ok.png":  "png",
+		"docs/notes.txt":   "plain",
+		"docs/sub/page.md": "# Page\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create file: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		want  []string // rule@line:column
+	}{
+		{
+			name: "valid links and images",
+			input: "# Index\n\n[guide](guide.md) [steps](guide.md#install-steps) [second](guide.md#install-steps-1)\n" +
+				"[legacy](guide.md#legacy) [page](sub/page.md#page) [notes](notes.txt) [dir](sub/)\n" +
+				"[escaped](sub/page%2Emd) [query](guide.md?plain=1#guide) [self](#index)\n\n![ok](img/ok.png)\n",
+			want: nil,
+		},
+		{
+			name:  "missing link target",
+			input: "# Index\n\nSee [old](old-guide.md) and [deep](sub/missing.md#x).\n",
+			want:  []string{"broken-link@3:5", "broken-link@3:29"},
+		},
+		{
+			name:  "missing heading in other document",
+			input: "# Index\n\n[renamed](guide.md#installation)\n",
+			want:  []string{"broken-link@3:1"},
+		},
+		{
+			name:  "fragments into non-Markdown files are not checked",
+			input: "# Index\n\n[notes](notes.txt#anything)\n",
+			want:  nil,
+		},
+		{
+			name:  "missing heading in this document",
+			input: "# Index\n\n## Usage\n\n[usage](#usage) [gone](#history)\n",
+			want:  []string{"broken-link@5:17"},
+		},
+		{
+			name:  "missing image",
+			input: "# Index\n\n![shot](img/missing.png)\n",
+			want:  []string{"missing-image@3:1"},
+		},
+		{
+			name:  "absolute and external references are skipped",
+			input: "# Index\n\n[site](https://example.com/x.md) [root](/abs.md) [mail](mailto:a@b.c)\n\n![remote](https://example.com/a.png)\n",
+			want:  nil,
+		},
+	}
+
+	conv := converter.NewGoldmarkConverter(converter.DefaultOptions())
+	val := validator.NewGoldmarkValidator(conv)
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(tmpDir, "docs", fmt.Sprintf("index%d.md", i))
+			if err := os.WriteFile(path, []byte(tt.input), 0644); err != nil {
+				t.Fatalf("Failed to create file: %v", err)
+			}
+			diags, err := val.ValidateFile(path)
+			if err != nil {
+				t.Fatalf("ValidateFile() unexpected error: %v", err)
+			}
+			var got []string
+			for _, d := range diags {
+				got = append(got, fmt.Sprintf("%s@%d:%d", d.Rule, d.Line, d.Column))
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ValidateFile() = %v, want %v (%v)", got, tt.want, diags)
+			}
+		})
+	}
+
+	t.Run("content without a file only checks own fragments", func(t *testing.T) {
+		diags, err := val.Validate([]byte("# Index\n\n[x](nowhere.md) ![y](nowhere.png) [z](#nowhere)\n"))
+		if err != nil {
+			t.Fatalf("Validate() unexpected error: %v", err)
+		}
+		if len(diags) != 1 || diags[0].Rule != validator.RuleBrokenLink || diags[0].Column != 35 {
+			t.Errorf("Validate() = %v, want one broken-link for #nowhere", diags)
+		}
+	})
+}
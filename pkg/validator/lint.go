@@ -0,0 +1,231 @@
+package validator
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+
+	"github.com/sgaunet/mdtohtml/pkg/frontmatter"
+)
+
+// Rule describes a lint rule run by Lint.
+type Rule struct {
+	// ID identifies the rule in LintConfig and in diagnostics.
+	ID string
+	// Description summarises what the rule reports.
+	Description string
+	// Severity is the severity of the rule's diagnostics unless LintConfig
+	// overrides it.
+	Severity Severity
+
+	check func(d *lintDocument, report reportFunc)
+}
+
+// reportFunc records a finding at byte offset pos of the parsed body.
+type reportFunc func(pos int, format string, args ...any)
+
+// Rules returns every lint rule in the order they run.
+func Rules() []Rule {
+	return slices.Clone(rules)
+}
+
+// LintConfig selects the lint rules to run and their severity. The zero
+// value runs every rule with its default severity.
+type LintConfig struct {
+	// Enabled, when non-empty, restricts linting to the listed rule IDs.
+	Enabled []string
+	// Disabled lists rule IDs that are not run.
+	Disabled []string
+	// Severity overrides the default severity of rules by ID.
+	Severity map[string]Severity
+	// External checks the absolute URLs of documents for the external-link
+	// and external-redirect rules. Those rules report nothing when it is nil.
+	External *ExternalChecker
+}
+
+// Validate checks that every rule ID named by the configuration exists.
+func (c LintConfig) Validate() error {
+	ids := slices.Concat(c.Enabled, c.Disabled)
+	for id := range c.Severity {
+		ids = append(ids, id)
+	}
+	for _, id := range ids {
+		if !slices.ContainsFunc(rules, func(r Rule) bool { return r.ID == id }) {
+			return fmt.Errorf("%w: %q", ErrUnknownRule, id)
+		}
+	}
+	return nil
+}
+
+// active returns the rules selected by the configuration, with their
+// severity overrides applied.
+func (c LintConfig) active() []Rule {
+	var active []Rule
+	for _, r := range rules {
+		if len(c.Enabled) > 0 && !slices.Contains(c.Enabled, r.ID) {
+			continue
+		}
+		if slices.Contains(c.Disabled, r.ID) {
+			continue
+		}
+		if s, ok := c.Severity[r.ID]; ok {
+			r.Severity = s
+		}
+		active = append(active, r)
+	}
+	return active
+}
+
+// lintParser parses documents for Lint with the extensions and heading IDs
+// of converter.GoldmarkConverter, so rules see the tree the converter renders.
+var lintParser = goldmark.New(
+	goldmark.WithExtensions(
+		extension.GFM,
+		extension.DefinitionList,
+		extension.Footnote,
+		&unusedFootnoteExtension{},
+	),
+	goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+).Parser()
+
+// lintDocument is a parsed document handed to the rule checks.
+type lintDocument struct {
+	content []byte // the linted content, including front matter
+	offset  int    // length of the front matter in content
+	source  []byte // the parsed body
+	doc     ast.Node
+	pc      parser.Context
+
+	// dir is the directory relative links are resolved against; empty when
+	// the content does not come from a file.
+	dir     string
+	docIDs  map[string]bool
+	targets map[string]map[string]bool
+
+	// external checks absolute URLs; the results are shared by the external
+	// rules.
+	external        *ExternalChecker
+	externalLinks   []externalLink
+	externalResults []LinkResult
+}
+
+// position converts byte offset pos of the body to a 1-based line and column in
+// the content.
+func (d *lintDocument) position(pos int) (int, int) {
+	return position(d.content, d.offset+pos)
+}
+
+// line returns the 1-based line in the content of byte offset pos of the body.
+func (d *lintDocument) line(pos int) int {
+	line, _ := d.position(pos)
+	return line
+}
+
+// Lint runs the rules selected by config over Markdown content and returns
+// their diagnostics sorted by position. Front matter is skipped, but line
+// numbers count it. Content with invalid front matter is linted as a whole.
+// Relative links and images are not resolved, as content has no location;
+// use a GoldmarkValidator's ValidateFile for that.
+func Lint(content []byte, config LintConfig) []Diagnostic {
+	return lint(content, "", config)
+}
+
+// lint is Lint for content read from a file in dir, against which relative
+// links and images are resolved. An empty dir disables those checks.
+func lint(content []byte, dir string, config LintConfig) []Diagnostic {
+	body := content
+	if _, b, err := frontmatter.Split(content); err == nil {
+		body = b
+	}
+	// Split returns a suffix of content, so offsets into body are shifted by
+	// the length of the front matter.
+	offset := len(content) - len(body)
+
+	pc := parser.NewContext()
+	d := &lintDocument{
+		content:  content,
+		offset:   offset,
+		source:   body,
+		doc:      lintParser.Parse(text.NewReader(body), parser.WithContext(pc)),
+		pc:       pc,
+		dir:      dir,
+		external: config.External,
+	}
+
+	var diags []Diagnostic
+	for _, r := range config.active() {
+		r.check(d, func(pos int, format string, args ...any) {
+			line, col := d.position(pos)
+			diags = append(diags, Diagnostic{
+				Line:     line,
+				Column:   col,
+				Rule:     r.ID,
+				Severity: r.Severity,
+				Message:  fmt.Sprintf(format, args...),
+			})
+		})
+	}
+	sort.SliceStable(diags, func(i, j int) bool {
+		if diags[i].Line != diags[j].Line {
+			return diags[i].Line < diags[j].Line
+		}
+		return diags[i].Column < diags[j].Column
+	})
+	return diags
+}
+
+// position converts byte offset pos of content to a 1-based line and column.
+func position(content []byte, pos int) (int, int) {
+	pos = min(max(pos, 0), len(content))
+	before := content[:pos]
+	line := bytes.Count(before, []byte{'\n'}) + 1
+	lineStart := bytes.LastIndexByte(before, '\n') + 1
+	return line, utf8.RuneCount(before[lineStart:]) + 1
+}
+
+// unusedFootnotesKey stores the footnote definitions that are never
+// referenced, as recorded by unusedFootnoteTransformer.
+var unusedFootnotesKey = parser.NewContextKey()
+
+// unusedFootnoteExtension records unreferenced footnote definitions before
+// the footnote extension removes them from the tree.
+type unusedFootnoteExtension struct{}
+
+// Extend implements goldmark.Extender.
+func (e *unusedFootnoteExtension) Extend(m goldmark.Markdown) {
+	// Transformers run in ascending priority order. The footnote transformer
+	// runs at 999 and drops unreferenced definitions, so this one runs first.
+	const transformerPriority = 998
+	m.Parser().AddOptions(parser.WithASTTransformers(
+		util.Prioritized(&unusedFootnoteTransformer{}, transformerPriority),
+	))
+}
+
+// unusedFootnoteTransformer stores the unreferenced footnote nodes in the
+// parser context under unusedFootnotesKey.
+type unusedFootnoteTransformer struct{}
+
+// Transform implements parser.ASTTransformer.
+func (t *unusedFootnoteTransformer) Transform(doc *ast.Document, _ text.Reader, pc parser.Context) {
+	var unused []*east.Footnote
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if fn, ok := n.(*east.Footnote); ok && entering && fn.Index < 0 {
+			unused = append(unused, fn)
+		}
+		return ast.WalkContinue, nil
+	})
+	pc.Set(unusedFootnotesKey, unused)
+}
+
+// Compile-time interface check.
+var _ goldmark.Extender = (*unusedFootnoteExtension)(nil)
@@ -0,0 +1,221 @@
+package validator_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/sgaunet/mdtohtml/pkg/converter"
+	"github.com/sgaunet/mdtohtml/pkg/validator"
+)
+
+// TestLint tests that each lint rule reports its findings with positions
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string // rule@line:column
+	}{
+		{
+			name:  "clean document",
+			input: "# Title\n\n## Section\n\nSee [docs][ref] and ![logo](logo.png).[^1]\n\n[ref]: /docs\n[^1]: Note.\n",
+			want:  nil,
+		},
+		{
+			name:  "skipped heading level",
+			input: "# Title\n\n### Deep\n\n## Back\n\n#### Deeper\n",
+			want:  []string{"heading-increment@3:1", "heading-increment@7:1"},
+		},
+		{
+			name:  "duplicate heading IDs",
+			input: "## Setup\n\n## Setup\n\n## setup!\n",
+			want:  []string{"duplicate-heading-id@3:1", "duplicate-heading-id@5:1"},
+		},
+		{
+			name:  "empty links",
+			input: "[](page.md) and [text]() and [hash](#)\n",
+			want:  []string{"empty-link@1:1", "empty-link@1:17", "empty-link@1:30"},
+		},
+		{
+			name:  "image as link text is not empty",
+			input: "[![badge](b.svg)](https://example.com)\n",
+			want:  nil,
+		},
+		{
+			name:  "image without alt text",
+			input: "Intro ![](a.png) and ![alt](b.png)\n",
+			want:  []string{"image-alt@1:7"},
+		},
+		{
+			name:  "undefined reference links",
+			input: "A [full][missing], [collapsed][] and [ok][def].\n\n[def]: /x\n",
+			want:  []string{"undefined-reference@1:3", "undefined-reference@1:20"},
+		},
+		{
+			name:  "escaped brackets and code spans are not references",
+			input: "\\[a\\]\\[b\\] and `[c][d]`\n",
+			want:  nil,
+		},
+		{
+			name:  "reference labels are case-insensitive",
+			input: "[Go][GOLANG]\n\n[golang]: https://go.dev\n",
+			want:  nil,
+		},
+		{
+			name:  "unused footnote",
+			input: "Text[^used].\n\n[^used]: Used.\n[^spare]: Never cited.\n",
+			want:  []string{"unused-footnote@4:1"},
+		},
+		{
+			name:  "multiple H1",
+			input: "# One\n\n# Two\n\nSetext\n======\n",
+			want:  []string{"single-h1@3:1", "single-h1@5:1"},
+		},
+		{
+			name:  "front matter counts towards line numbers",
+			input: "---\ntitle: Doc\n---\n# One\n\n# Two\n",
+			want:  []string{"single-h1@6:1"},
+		},
+		{
+			name:  "columns count characters",
+			input: "Été ![](a.png)\n",
+			want:  []string{"image-alt@1:5"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, d := range validator.Lint([]byte(tt.input), validator.LintConfig{}) {
+				got = append(got, fmt.Sprintf("%s@%d:%d", d.Rule, d.Line, d.Column))
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Lint() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestLint_Config tests enabling, disabling and re-grading rules
+func TestLint_Config(t *testing.T) {
+	input := []byte("# One\n\n# Two\n\n![](a.png)\n")
+
+	tests := []struct {
+		name   string
+		config validator.LintConfig
+		want   []string // rule:severity
+	}{
+		{
+			name:   "defaults",
+			config: validator.LintConfig{},
+			want:   []string{"single-h1:warning", "image-alt:warning"},
+		},
+		{
+			name:   "disabled rule",
+			config: validator.LintConfig{Disabled: []string{validator.RuleSingleH1}},
+			want:   []string{"image-alt:warning"},
+		},
+		{
+			name:   "enabled rules only",
+			config: validator.LintConfig{Enabled: []string{validator.RuleSingleH1}},
+			want:   []string{"single-h1:warning"},
+		},
+		{
+			name: "severity override",
+			config: validator.LintConfig{
+				Severity: map[string]validator.Severity{validator.RuleImageAlt: validator.SeverityError},
+			},
+			want: []string{"single-h1:warning", "image-alt:error"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, d := range validator.Lint(input, tt.config) {
+				got = append(got, d.Rule+":"+d.Severity.String())
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Lint() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestLintConfig_Validate tests that unknown rule IDs are rejected
+func TestLintConfig_Validate(t *testing.T) {
+	if err := (validator.LintConfig{Disabled: []string{validator.RuleImageAlt}}).Validate(); err != nil {
+		t.Errorf("Validate() unexpected error: %v", err)
+	}
+	configs := []validator.LintConfig{
+		{Enabled: []string{"no-such-rule"}},
+		{Disabled: []string{"no-such-rule"}},
+		{Severity: map[string]validator.Severity{"no-such-rule": validator.SeverityInfo}},
+	}
+	for _, c := range configs {
+		if err := c.Validate(); !errors.Is(err, validator.ErrUnknownRule) {
+			t.Errorf("Validate(%+v) = %v, want ErrUnknownRule", c, err)
+		}
+	}
+}
+
+// TestParseSeverity tests parsing of severity names
+func TestParseSeverity(t *testing.T) {
+	for _, s := range []validator.Severity{validator.SeverityInfo, validator.SeverityWarning, validator.SeverityError} {
+		got, err := validator.ParseSeverity(s.String())
+		if err != nil || got != s {
+			t.Errorf("ParseSeverity(%q) = %v, %v", s.String(), got, err)
+		}
+	}
+	if got, err := validator.ParseSeverity("WARNING"); err != nil || got != validator.SeverityWarning {
+		t.Errorf("ParseSeverity(WARNING) = %v, %v", got, err)
+	}
+	if _, err := validator.ParseSeverity("fatal"); !errors.Is(err, validator.ErrUnknownSeverity) {
+		t.Errorf("ParseSeverity(fatal) = %v, want ErrUnknownSeverity", err)
+	}
+}
+
+// TestGoldmarkValidator_Diagnostics tests that Validate and ValidateFile return lint findings as diagnostics
+func TestGoldmarkValidator_Diagnostics(t *testing.T) {
+	conv := converter.NewGoldmarkConverter(converter.DefaultOptions())
+	val, err := validator.NewGoldmarkValidatorWithLint(conv, validator.LintConfig{
+		Severity: map[string]validator.Severity{validator.RuleSingleH1: validator.SeverityError},
+	})
+	if err != nil {
+		t.Fatalf("NewGoldmarkValidatorWithLint() error: %v", err)
+	}
+
+	diags, err := val.Validate([]byte("# One\n\n# Two\n"))
+	if err != nil {
+		t.Fatalf("Validate() unexpected error: %v", err)
+	}
+	want := []validator.Diagnostic{{
+		Line: 3, Column: 1, Rule: validator.RuleSingleH1, Severity: validator.SeverityError,
+		Message: "multiple level 1 headings; the first is on line 1",
+	}}
+	if !slices.Equal(diags, want) {
+		t.Errorf("Validate() = %+v, want %+v", diags, want)
+	}
+
+	path := filepath.Join(t.TempDir(), "doc.md")
+	if err := os.WriteFile(path, []byte("[text][missing]\n"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	diags, err = val.ValidateFile(path)
+	if err != nil {
+		t.Fatalf("ValidateFile() unexpected error: %v", err)
+	}
+	if len(diags) != 1 || diags[0].File != path || validator.CountSeverity(diags, validator.SeverityError) != 1 {
+		t.Errorf("ValidateFile() = %+v, want one error in %s", diags, path)
+	}
+	if got := diags[0].String(); got != path+`:1:1: error: reference link label "missing" is not defined (undefined-reference)` {
+		t.Errorf("Diagnostic.String() = %q", got)
+	}
+
+	if _, err := validator.NewGoldmarkValidatorWithLint(conv, validator.LintConfig{Disabled: []string{"bogus"}}); !errors.Is(err, validator.ErrUnknownRule) {
+		t.Errorf("NewGoldmarkValidatorWithLint() with unknown rule = %v, want ErrUnknownRule", err)
+	}
+}
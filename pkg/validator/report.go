@@ -0,0 +1,214 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// OutputFormat selects how WriteDiagnostics formats diagnostics.
+type OutputFormat string
+
+// Supported output formats.
+const (
+	// FormatText writes one "file:line:column: severity: message (rule)" line
+	// per diagnostic.
+	FormatText OutputFormat = "text"
+	// FormatJSON writes a JSON array of diagnostics.
+	FormatJSON OutputFormat = "json"
+	// FormatSARIF writes a SARIF 2.1.0 log, as consumed by code scanning tools.
+	FormatSARIF OutputFormat = "sarif"
+	// FormatGitHub writes GitHub Actions workflow commands that annotate the
+	// offending lines of a pull request.
+	FormatGitHub OutputFormat = "github"
+)
+
+// outputFormats lists the supported formats in the order shown to users.
+var outputFormats = []OutputFormat{FormatText, FormatJSON, FormatSARIF, FormatGitHub}
+
+// ParseOutputFormat returns the OutputFormat named by name, ignoring case.
+func ParseOutputFormat(name string) (OutputFormat, error) {
+	for _, f := range outputFormats {
+		if strings.EqualFold(name, string(f)) {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %q (use text, json, sarif or github)", ErrUnknownOutputFormat, name)
+}
+
+// WriteDiagnostics writes diags to w in the given format.
+func WriteDiagnostics(w io.Writer, format OutputFormat, diags []Diagnostic) error {
+	var err error
+	switch format {
+	case FormatText:
+		for _, d := range diags {
+			if _, err = fmt.Fprintln(w, d); err != nil {
+				break
+			}
+		}
+	case FormatJSON:
+		if diags == nil {
+			diags = []Diagnostic{}
+		}
+		err = writeJSON(w, diags)
+	case FormatSARIF:
+		err = writeJSON(w, sarifLog(diags))
+	case FormatGitHub:
+		for _, d := range diags {
+			if _, err = fmt.Fprintln(w, githubCommand(d)); err != nil {
+				break
+			}
+		}
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownOutputFormat, format)
+	}
+	if err != nil {
+		return fmt.Errorf("error writing diagnostics: %w", err)
+	}
+	return nil
+}
+
+// writeJSON writes v to w as indented JSON.
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// githubLevels maps severities to GitHub Actions annotation commands.
+var githubLevels = map[Severity]string{
+	SeverityInfo:    "notice",
+	SeverityWarning: "warning",
+	SeverityError:   "error",
+}
+
+// githubCommand formats d as a GitHub Actions annotation, e.g.
+// "::error file=doc.md,line=3,col=1,title=empty-link::link has no text".
+func githubCommand(d Diagnostic) string {
+	props := []string{
+		"file=" + githubEscapeProperty(filepath.ToSlash(d.File)),
+		"line=" + strconv.Itoa(d.Line),
+		"col=" + strconv.Itoa(d.Column),
+		"title=" + githubEscapeProperty(d.Rule),
+	}
+	if d.File == "" {
+		props = props[1:]
+	}
+	return "::" + githubLevels[d.Severity] + " " + strings.Join(props, ",") + "::" + githubEscapeData(d.Message)
+}
+
+// githubEscapeData escapes the message of a workflow command.
+func githubEscapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// githubEscapeProperty escapes a property value of a workflow command.
+func githubEscapeProperty(s string) string {
+	return strings.NewReplacer(":", "%3A", ",", "%2C").Replace(githubEscapeData(s))
+}
+
+// SARIF 2.1.0 constants used by sarifLog.
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	toolName     = "mdtohtml"
+	toolURI      = "https://github.com/sgaunet/mdtohtml"
+)
+
+// sarifLevels maps severities to SARIF result levels.
+var sarifLevels = map[Severity]string{
+	SeverityInfo:    "note",
+	SeverityWarning: "warning",
+	SeverityError:   "error",
+}
+
+// The sarif types model the subset of SARIF 2.1.0 that WriteDiagnostics
+// produces.
+type (
+	sarifDoc struct {
+		Version string     `json:"version"`
+		Schema  string     `json:"$schema"`
+		Runs    []sarifRun `json:"runs"`
+	}
+	sarifRun struct {
+		Tool       sarifTool     `json:"tool"`
+		ColumnKind string        `json:"columnKind"`
+		Results    []sarifResult `json:"results"`
+	}
+	sarifTool struct {
+		Driver sarifDriver `json:"driver"`
+	}
+	sarifDriver struct {
+		Name           string      `json:"name"`
+		InformationURI string      `json:"informationUri"`
+		Rules          []sarifRule `json:"rules"`
+	}
+	sarifRule struct {
+		ID                   string       `json:"id"`
+		ShortDescription     sarifMessage `json:"shortDescription"`
+		DefaultConfiguration sarifConfig  `json:"defaultConfiguration"`
+	}
+	sarifConfig struct {
+		Level string `json:"level"`
+	}
+	sarifMessage struct {
+		Text string `json:"text"`
+	}
+	sarifResult struct {
+		RuleID    string          `json:"ruleId"`
+		Level     string          `json:"level"`
+		Message   sarifMessage    `json:"message"`
+		Locations []sarifLocation `json:"locations"`
+	}
+	sarifLocation struct {
+		PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+	}
+	sarifPhysicalLocation struct {
+		ArtifactLocation sarifArtifact `json:"artifactLocation"`
+		Region           sarifRegion   `json:"region"`
+	}
+	sarifArtifact struct {
+		URI string `json:"uri"`
+	}
+	sarifRegion struct {
+		StartLine   int `json:"startLine"`
+		StartColumn int `json:"startColumn"`
+	}
+)
+
+// sarifLog builds a SARIF log with one run holding diags. The run describes
+// every lint rule, so viewers can show rule help for any result.
+func sarifLog(diags []Diagnostic) sarifDoc {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           toolName,
+			InformationURI: toolURI,
+			Rules:          []sarifRule{},
+		}},
+		// Diagnostic columns count characters, not UTF-16 code units.
+		ColumnKind: "unicodeCodePoints",
+		Results:    []sarifResult{},
+	}
+	for _, r := range rules {
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+			ID:                   r.ID,
+			ShortDescription:     sarifMessage{Text: r.Description},
+			DefaultConfiguration: sarifConfig{Level: sarifLevels[r.Severity]},
+		})
+	}
+	for _, d := range diags {
+		run.Results = append(run.Results, sarifResult{
+			RuleID:  d.Rule,
+			Level:   sarifLevels[d.Severity],
+			Message: sarifMessage{Text: d.Message},
+			Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifact{URI: filepath.ToSlash(d.File)},
+				Region:           sarifRegion{StartLine: d.Line, StartColumn: d.Column},
+			}}},
+		})
+	}
+	return sarifDoc{Version: sarifVersion, Schema: sarifSchema, Runs: []sarifRun{run}}
+}
@@ -0,0 +1,149 @@
+package validator_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sgaunet/mdtohtml/pkg/validator"
+)
+
+// reportDiagnostics are the findings written by the report tests.
+var reportDiagnostics = []validator.Diagnostic{
+	{File: "docs/a.md", Line: 3, Column: 1, Rule: validator.RuleHeadingIncrement, Severity: validator.SeverityWarning,
+		Message: "heading level 3 follows level 1; expected level 2 or lower"},
+	{File: "docs/a.md", Line: 7, Column: 9, Rule: validator.RuleUndefinedReference, Severity: validator.SeverityError,
+		Message: `reference link label "x,y" is not defined`},
+	{File: "docs/b.md", Line: 1, Column: 4, Rule: validator.RuleImageAlt, Severity: validator.SeverityInfo,
+		Message: "100% missing"},
+}
+
+// TestWriteDiagnostics_Text tests the text output format
+func TestWriteDiagnostics_Text(t *testing.T) {
+	var buf bytes.Buffer
+	if err := validator.WriteDiagnostics(&buf, validator.FormatText, reportDiagnostics); err != nil {
+		t.Fatalf("WriteDiagnostics() error: %v", err)
+	}
+	want := "docs/a.md:3:1: warning: heading level 3 follows level 1; expected level 2 or lower (heading-increment)\n" +
+		"docs/a.md:7:9: error: reference link label \"x,y\" is not defined (undefined-reference)\n" +
+		"docs/b.md:1:4: info: 100% missing (image-alt)\n"
+	if buf.String() != want {
+		t.Errorf("WriteDiagnostics() =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+// TestWriteDiagnostics_JSON tests that the JSON output round-trips
+func TestWriteDiagnostics_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := validator.WriteDiagnostics(&buf, validator.FormatJSON, reportDiagnostics); err != nil {
+		t.Fatalf("WriteDiagnostics() error: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"severity": "error"`) {
+		t.Errorf("JSON output should name severities, got:\n%s", buf.String())
+	}
+	var got []validator.Diagnostic
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON output: %v", err)
+	}
+	if len(got) != len(reportDiagnostics) || got[1] != reportDiagnostics[1] {
+		t.Errorf("JSON round trip = %+v, want %+v", got, reportDiagnostics)
+	}
+
+	buf.Reset()
+	if err := validator.WriteDiagnostics(&buf, validator.FormatJSON, nil); err != nil {
+		t.Fatalf("WriteDiagnostics() error: %v", err)
+	}
+	if strings.TrimSpace(buf.String()) != "[]" {
+		t.Errorf("JSON output without diagnostics = %q, want []", buf.String())
+	}
+}
+
+// TestWriteDiagnostics_SARIF tests the structure of the SARIF output
+func TestWriteDiagnostics_SARIF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := validator.WriteDiagnostics(&buf, validator.FormatSARIF, reportDiagnostics); err != nil {
+		t.Fatalf("WriteDiagnostics() error: %v", err)
+	}
+
+	var log struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Name  string `json:"name"`
+					Rules []struct {
+						ID string `json:"id"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				Level     string `json:"level"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+						Region struct {
+							StartLine   int `json:"startLine"`
+							StartColumn int `json:"startColumn"`
+						} `json:"region"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("invalid SARIF output: %v", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("SARIF log version %q with %d runs", log.Version, len(log.Runs))
+	}
+	run := log.Runs[0]
+	if run.Tool.Driver.Name != "mdtohtml" || len(run.Tool.Driver.Rules) != len(validator.Rules()) {
+		t.Errorf("SARIF driver = %+v", run.Tool.Driver)
+	}
+	if len(run.Results) != len(reportDiagnostics) {
+		t.Fatalf("SARIF results = %d, want %d", len(run.Results), len(reportDiagnostics))
+	}
+	levels := []string{"warning", "error", "note"}
+	for i, r := range run.Results {
+		d := reportDiagnostics[i]
+		loc := r.Locations[0].PhysicalLocation
+		if r.RuleID != d.Rule || r.Level != levels[i] || loc.ArtifactLocation.URI != d.File ||
+			loc.Region.StartLine != d.Line || loc.Region.StartColumn != d.Column {
+			t.Errorf("SARIF result %d = %+v, want %+v", i, r, d)
+		}
+	}
+}
+
+// TestWriteDiagnostics_GitHub tests the GitHub Actions annotation format
+func TestWriteDiagnostics_GitHub(t *testing.T) {
+	var buf bytes.Buffer
+	if err := validator.WriteDiagnostics(&buf, validator.FormatGitHub, reportDiagnostics); err != nil {
+		t.Fatalf("WriteDiagnostics() error: %v", err)
+	}
+	want := "::warning file=docs/a.md,line=3,col=1,title=heading-increment::heading level 3 follows level 1; expected level 2 or lower\n" +
+		"::error file=docs/a.md,line=7,col=9,title=undefined-reference::reference link label \"x,y\" is not defined\n" +
+		"::notice file=docs/b.md,line=1,col=4,title=image-alt::100%25 missing\n"
+	if buf.String() != want {
+		t.Errorf("WriteDiagnostics() =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+// TestParseOutputFormat tests parsing of output format names
+func TestParseOutputFormat(t *testing.T) {
+	for _, name := range []string{"text", "json", "sarif", "github", "SARIF"} {
+		if _, err := validator.ParseOutputFormat(name); err != nil {
+			t.Errorf("ParseOutputFormat(%q) unexpected error: %v", name, err)
+		}
+	}
+	if _, err := validator.ParseOutputFormat("xml"); !errors.Is(err, validator.ErrUnknownOutputFormat) {
+		t.Errorf("ParseOutputFormat(xml) = %v, want ErrUnknownOutputFormat", err)
+	}
+	if err := validator.WriteDiagnostics(&bytes.Buffer{}, "xml", nil); !errors.Is(err, validator.ErrUnknownOutputFormat) {
+		t.Errorf("WriteDiagnostics(xml) = %v, want ErrUnknownOutputFormat", err)
+	}
+}
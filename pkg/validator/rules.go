@@ -0,0 +1,274 @@
+package validator
+
+import (
+	"bytes"
+	"regexp"
+
+	"github.com/yuin/goldmark/ast"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/util"
+)
+
+// Rule IDs accepted by LintConfig.
+const (
+	RuleHeadingIncrement   = "heading-increment"
+	RuleDuplicateHeadingID = "duplicate-heading-id"
+	RuleEmptyLink          = "empty-link"
+	RuleImageAlt           = "image-alt"
+	RuleUndefinedReference = "undefined-reference"
+	RuleUnusedFootnote     = "unused-footnote"
+	RuleSingleH1           = "single-h1"
+	RuleBrokenLink         = "broken-link"
+	RuleMissingImage       = "missing-image"
+	RuleExternalLink       = "external-link"
+	RuleExternalRedirect   = "external-redirect"
+)
+
+// rules is the registry of lint rules, in the order they run. The external
+// rules only report when LintConfig.External is set, as the validate command
+// does for --check-external.
+var rules = []Rule{
+	{
+		ID:          RuleHeadingIncrement,
+		Description: "heading levels increase by one at a time",
+		Severity:    SeverityWarning,
+		check:       checkHeadingIncrement,
+	},
+	{
+		ID:          RuleDuplicateHeadingID,
+		Description: "headings generate distinct IDs",
+		Severity:    SeverityWarning,
+		check:       checkDuplicateHeadingID,
+	},
+	{
+		ID:          RuleEmptyLink,
+		Description: "links have a destination and text",
+		Severity:    SeverityError,
+		check:       checkEmptyLink,
+	},
+	{
+		ID:          RuleImageAlt,
+		Description: "images have alt text",
+		Severity:    SeverityWarning,
+		check:       checkImageAlt,
+	},
+	{
+		ID:          RuleUndefinedReference,
+		Description: "reference-style links have a definition",
+		Severity:    SeverityError,
+		check:       checkUndefinedReference,
+	},
+	{
+		ID:          RuleUnusedFootnote,
+		Description: "footnote definitions are referenced",
+		Severity:    SeverityWarning,
+		check:       checkUnusedFootnote,
+	},
+	{
+		ID:          RuleSingleH1,
+		Description: "the document has at most one level 1 heading",
+		Severity:    SeverityWarning,
+		check:       checkSingleH1,
+	},
+	{
+		ID:          RuleBrokenLink,
+		Description: "relative links point at existing files and heading IDs",
+		Severity:    SeverityError,
+		check:       checkBrokenLink,
+	},
+	{
+		ID:          RuleMissingImage,
+		Description: "relative image sources exist",
+		Severity:    SeverityError,
+		check:       checkMissingImage,
+	},
+	{
+		ID:          RuleExternalLink,
+		Description: "external URLs respond without an error (with --check-external)",
+		Severity:    SeverityError,
+		check:       checkExternalLink,
+	},
+	{
+		ID:          RuleExternalRedirect,
+		Description: "external URLs do not redirect (with --check-external)",
+		Severity:    SeverityWarning,
+		check:       checkExternalRedirect,
+	},
+}
+
+// walkEntering calls fn for every node of doc on entering it.
+func walkEntering(doc ast.Node, fn func(n ast.Node)) {
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if entering {
+			fn(n)
+		}
+		return ast.WalkContinue, nil
+	})
+}
+
+// checkHeadingIncrement reports headings more than one level deeper than the
+// heading before them, e.g. an H4 directly under an H2.
+func checkHeadingIncrement(d *lintDocument, report reportFunc) {
+	prev := 0
+	walkEntering(d.doc, func(n ast.Node) {
+		h, ok := n.(*ast.Heading)
+		if !ok {
+			return
+		}
+		if prev > 0 && h.Level > prev+1 {
+			report(h.Pos(), "heading level %d follows level %d; expected level %d or lower", h.Level, prev, prev+1)
+		}
+		prev = h.Level
+	})
+}
+
+// checkDuplicateHeadingID reports headings whose text yields the same ID as
+// an earlier heading. The converter makes such IDs unique by appending a
+// number, so links to the later headings need a guessed suffix.
+func checkDuplicateHeadingID(d *lintDocument, report reportFunc) {
+	seen := make(map[string]int)
+	walkEntering(d.doc, func(n ast.Node) {
+		h, ok := n.(*ast.Heading)
+		if !ok {
+			return
+		}
+		var line []byte
+		if last := h.Lines().Len() - 1; last >= 0 {
+			seg := h.Lines().At(last)
+			line = seg.Value(d.source)
+		}
+		// A fresh context generates the ID the heading would get if it were
+		// the first one with this text.
+		id := string(parser.NewContext().IDs().Generate(line, ast.KindHeading))
+		pos := h.Pos()
+		if first, ok := seen[id]; ok {
+			report(pos, "duplicate heading ID %q, first used by the heading on line %d", id, d.line(first))
+			return
+		}
+		seen[id] = pos
+	})
+}
+
+// checkEmptyLink reports links without a destination or without text.
+func checkEmptyLink(d *lintDocument, report reportFunc) {
+	walkEntering(d.doc, func(n ast.Node) {
+		link, ok := n.(*ast.Link)
+		if !ok {
+			return
+		}
+		switch dest := string(bytes.TrimSpace(link.Destination)); {
+		case dest == "" || dest == "#":
+			report(link.Pos(), "link has no destination")
+		case len(bytes.TrimSpace(inlineText(link, d.source))) == 0:
+			report(link.Pos(), "link to %q has no text", dest)
+		}
+	})
+}
+
+// checkImageAlt reports images without alt text.
+func checkImageAlt(d *lintDocument, report reportFunc) {
+	walkEntering(d.doc, func(n ast.Node) {
+		img, ok := n.(*ast.Image)
+		if ok && len(bytes.TrimSpace(inlineText(img, d.source))) == 0 {
+			report(img.Pos(), "image %q has no alt text", img.Destination)
+		}
+	})
+}
+
+// referencePattern matches full ("[text][label]") and collapsed
+// ("[text][]") reference links.
+var referencePattern = regexp.MustCompile(`\[([^\[\]]+)\]\[([^\[\]]*)\]`)
+
+// checkUndefinedReference reports full and collapsed reference links whose
+// label has no definition. Goldmark leaves such links as literal text, so the
+// rule scans runs of adjacent text nodes in the source. Shortcut references
+// ("[label]") are not reported, as bracketed prose is common.
+func checkUndefinedReference(d *lintDocument, report reportFunc) {
+	scan := func(start, stop int) {
+		run := d.source[start:stop]
+		for _, m := range referencePattern.FindAllSubmatchIndex(run, -1) {
+			if m[0] > 0 && run[m[0]-1] == '\\' {
+				continue
+			}
+			label := run[m[4]:m[5]]
+			if len(label) == 0 {
+				label = run[m[2]:m[3]]
+			}
+			if len(bytes.TrimSpace(label)) == 0 || label[0] == '^' {
+				continue
+			}
+			if _, ok := d.pc.Reference(util.ToLinkReference(label)); !ok {
+				report(start+m[0], "reference link label %q is not defined", label)
+			}
+		}
+	}
+
+	walkEntering(d.doc, func(n ast.Node) {
+		if n.Type() != ast.TypeBlock {
+			return
+		}
+		start, stop := -1, -1
+		for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+			t, ok := c.(*ast.Text)
+			if !ok {
+				if start >= 0 {
+					scan(start, stop)
+				}
+				start = -1
+				continue
+			}
+			if start < 0 {
+				start = t.Segment.Start
+			}
+			stop = t.Segment.Stop
+		}
+		if start >= 0 {
+			scan(start, stop)
+		}
+	})
+}
+
+// checkUnusedFootnote reports footnote definitions that are never referenced.
+func checkUnusedFootnote(d *lintDocument, report reportFunc) {
+	unused, _ := d.pc.Get(unusedFootnotesKey).([]*east.Footnote)
+	for _, fn := range unused {
+		report(fn.Pos(), "footnote %q is defined but never referenced", fn.Ref)
+	}
+}
+
+// checkSingleH1 reports every level 1 heading after the first.
+func checkSingleH1(d *lintDocument, report reportFunc) {
+	first := -1
+	walkEntering(d.doc, func(n ast.Node) {
+		h, ok := n.(*ast.Heading)
+		if !ok || h.Level != 1 {
+			return
+		}
+		if first >= 0 {
+			report(h.Pos(), "multiple level 1 headings; the first is on line %d", d.line(first))
+			return
+		}
+		first = h.Pos()
+	})
+}
+
+// inlineText returns the text of the inline children of n, such as the text
+// of a link or the alt text of an image.
+func inlineText(n ast.Node, source []byte) []byte {
+	var buf bytes.Buffer
+	walkEntering(n, func(c ast.Node) {
+		switch c := c.(type) {
+		case *ast.Text:
+			buf.Write(c.Segment.Value(source))
+		case *ast.String:
+			buf.Write(c.Value)
+		case *ast.Image:
+			// An image inside a link counts as its content.
+			if c != n {
+				buf.WriteString("image")
+			}
+		}
+	})
+	return buf.Bytes()
+}
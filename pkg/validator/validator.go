@@ -3,9 +3,11 @@ package validator
 
 // Validator defines the interface for markdown validation.
 type Validator interface {
-	// Validate validates markdown content and returns an error if invalid
-	Validate(content []byte) error
+	// Validate validates markdown content and returns its diagnostics. The
+	// error is non-nil only when the content cannot be validated at all.
+	Validate(content []byte) ([]Diagnostic, error)
 
-	// ValidateFile validates a markdown file and returns an error if invalid
-	ValidateFile(path string) error
-}
\ No newline at end of file
+	// ValidateFile validates a markdown file and returns its diagnostics,
+	// with File set to path.
+	ValidateFile(path string) ([]Diagnostic, error)
+}
@@ -60,7 +60,7 @@ func TestGoldmarkValidator_Validate(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := val.Validate([]byte(tt.input))
+			_, err := val.Validate([]byte(tt.input))
 			if (err != nil) != tt.shouldErr {
 				t.Errorf("Validate() error = %v, shouldErr %v", err, tt.shouldErr)
 			}
@@ -111,7 +111,7 @@ func TestGoldmarkValidator_ValidateFile(t *testing.T) {
 			}
 
 			// Validate file
-			err := val.ValidateFile(filePath)
+			_, err := val.ValidateFile(filePath)
 			if (err != nil) != tt.shouldErr {
 				t.Errorf("ValidateFile() error = %v, shouldErr %v", err, tt.shouldErr)
 			}
@@ -120,7 +120,7 @@ func TestGoldmarkValidator_ValidateFile(t *testing.T) {
 
 	// Test nonexistent file
 	t.Run("nonexistent file", func(t *testing.T) {
-		err := val.ValidateFile(filepath.Join(tmpDir, "nonexistent.md"))
+		_, err := val.ValidateFile(filepath.Join(tmpDir, "nonexistent.md"))
 		if err == nil {
 			t.Error("ValidateFile() should return error for nonexistent file")
 		}
@@ -165,7 +165,7 @@ func TestGoldmarkValidator_WithDifferentOptions(t *testing.T) {
 			conv := converter.NewGoldmarkConverter(tt.options)
 			val := validator.NewGoldmarkValidator(conv)
 
-			err := val.Validate(input)
+			_, err := val.Validate(input)
 			if err != nil {
 				t.Errorf("Validate() with %s options failed: %v", tt.name, err)
 			}
@@ -212,7 +212,7 @@ func TestGoldmarkValidator_Validate_ErrorScenarios(t *testing.T) {
 			conv := tt.setupConv()
 			val := validator.NewGoldmarkValidator(conv)
 			
-			err := val.Validate(tt.input)
+			_, err := val.Validate(tt.input)
 			
 			if (err != nil) != tt.expectError {
 				t.Errorf("Validate() error = %v, expectError %v", err, tt.expectError)
@@ -240,7 +240,7 @@ func TestGoldmarkValidator_ValidateFile_AdvancedErrors(t *testing.T) {
 		conv := converter.NewGoldmarkConverter(converter.DefaultOptions())
 		val := validator.NewGoldmarkValidator(conv)
 		
-		err := val.ValidateFile(restrictedFile)
+		_, err := val.ValidateFile(restrictedFile)
 		if err == nil {
 			t.Error("Expected error for unreadable file")
 		}
@@ -255,7 +255,7 @@ func TestGoldmarkValidator_ValidateFile_AdvancedErrors(t *testing.T) {
 		conv := converter.NewGoldmarkConverter(converter.DefaultOptions())
 		val := validator.NewGoldmarkValidator(conv)
 		
-		err := val.ValidateFile(emptyFile)
+		_, err := val.ValidateFile(emptyFile)
 		if err != nil {
 			t.Errorf("Empty file should be valid: %v", err)
 		}
@@ -275,7 +275,7 @@ func TestGoldmarkValidator_ValidateFile_AdvancedErrors(t *testing.T) {
 		conv := converter.NewGoldmarkConverter(converter.DefaultOptions())
 		val := validator.NewGoldmarkValidator(conv)
 		
-		err := val.ValidateFile(largeFile)
+		_, err := val.ValidateFile(largeFile)
 		if err != nil {
 			t.Errorf("Large file validation failed: %v", err)
 		}
@@ -299,7 +299,7 @@ func TestGoldmarkValidator_ConcurrentValidation(t *testing.T) {
 	
 	for i, input := range inputs {
 		go func(index int, content []byte) {
-			err := val.Validate(content)
+			_, err := val.Validate(content)
 			errChan <- err
 		}(i, input)
 	}
@@ -324,7 +324,7 @@ func TestGoldmarkValidator_MemoryUsage(t *testing.T) {
 	// Validate many different inputs to test for memory leaks
 	for i := 0; i < 100; i++ {
 		input := []byte(fmt.Sprintf("# Test %d\n\nThis is test content number %d with some **bold** and *italic* text.", i, i))
-		err := val.Validate(input)
+		_, err := val.Validate(input)
 		if err != nil {
 			t.Errorf("Validation %d failed: %v", i, err)
 			break
@@ -384,7 +384,7 @@ More content here to make the document longer and more realistic for benchmarkin
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		err := val.Validate(input)
+		_, err := val.Validate(input)
 		if err != nil {
 			b.Fatalf("Validate() error = %v", err)
 		}
@@ -420,7 +420,7 @@ This is a test document for benchmarking file validation performance.
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		err := val.ValidateFile(testFile)
+		_, err := val.ValidateFile(testFile)
 		if err != nil {
 			b.Fatalf("ValidateFile() error = %v", err)
 		}
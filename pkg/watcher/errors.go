@@ -0,0 +1,7 @@
+package watcher
+
+import "errors"
+
+// ErrWatch is returned when a path cannot be watched or the file system
+// notification backend fails.
+var ErrWatch = errors.New("file watch failed")
@@ -0,0 +1,180 @@
+// Package watcher reports debounced batches of file system changes to
+// Markdown sources and stylesheets so that outputs can be rebuilt while
+// editing.
+package watcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// DefaultDebounce is how long the watcher waits after the last change before
+// reporting a batch. Editors often save a file as several writes or as a
+// write to a temporary file followed by a rename.
+const DefaultDebounce = 200 * time.Millisecond
+
+// Watcher watches files and directories and reports changes in batches.
+type Watcher struct {
+	fs       *fsnotify.Watcher
+	debounce time.Duration
+
+	// files holds the individually watched files; their parent directories
+	// are watched so that rename-on-save is seen.
+	files map[string]bool
+	// dirs maps watched directory roots to whether they are recursive.
+	dirs map[string]bool
+}
+
+// New creates a Watcher that reports changes once no further change has been
+// seen for debounce. Values below 1 default to DefaultDebounce.
+func New(debounce time.Duration) (*Watcher, error) {
+	if debounce <= 0 {
+		debounce = DefaultDebounce
+	}
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrWatch, err)
+	}
+	return &Watcher{
+		fs:       fsw,
+		debounce: debounce,
+		files:    make(map[string]bool),
+		dirs:     make(map[string]bool),
+	}, nil
+}
+
+// AddFile watches a single file.
+func (w *Watcher) AddFile(path string) error {
+	path = filepath.Clean(path)
+	if err := w.fs.Add(filepath.Dir(path)); err != nil {
+		return fmt.Errorf("%w '%s': %w", ErrWatch, path, err)
+	}
+	w.files[path] = true
+	return nil
+}
+
+// AddDir watches every file in dir and, if recursive is set, in all of its
+// subdirectories, including ones created later.
+func (w *Watcher) AddDir(dir string, recursive bool) error {
+	dir = filepath.Clean(dir)
+	w.dirs[dir] = w.dirs[dir] || recursive
+	if !recursive {
+		if err := w.fs.Add(dir); err != nil {
+			return fmt.Errorf("%w '%s': %w", ErrWatch, dir, err)
+		}
+		return nil
+	}
+	return w.addTree(dir)
+}
+
+// addTree watches root and every directory below it.
+func (w *Watcher) addTree(root string) error {
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		return w.fs.Add(path)
+	})
+	if err != nil {
+		return fmt.Errorf("%w '%s': %w", ErrWatch, root, err)
+	}
+	return nil
+}
+
+// Close stops watching.
+func (w *Watcher) Close() error {
+	if err := w.fs.Close(); err != nil {
+		return fmt.Errorf("%w: %w", ErrWatch, err)
+	}
+	return nil
+}
+
+// Run blocks until ctx is cancelled, calling onChange with the sorted,
+// de-duplicated paths that were created, written, renamed or removed since
+// the previous call. Paths are reported in the form they were added (e.g.
+// relative paths stay relative). onChange runs on Run's goroutine; changes
+// made while it runs are reported in the next batch. Run returns nil when
+// ctx is cancelled.
+func (w *Watcher) Run(ctx context.Context, onChange func(changed []string)) error {
+	pending := make(map[string]bool)
+	timer := time.NewTimer(w.debounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case event, ok := <-w.fs.Events:
+			if !ok {
+				return nil
+			}
+			if !w.handle(event) {
+				continue
+			}
+			pending[filepath.Clean(event.Name)] = true
+			timer.Reset(w.debounce)
+
+		case err, ok := <-w.fs.Errors:
+			if !ok {
+				return nil
+			}
+			if errors.Is(err, fsnotify.ErrEventOverflow) {
+				continue
+			}
+			return fmt.Errorf("%w: %w", ErrWatch, err)
+
+		case <-timer.C:
+			changed := make([]string, 0, len(pending))
+			for path := range pending {
+				changed = append(changed, path)
+			}
+			clear(pending)
+			slices.Sort(changed)
+			onChange(changed)
+		}
+	}
+}
+
+// handle starts watching directories created under a recursive root and
+// reports whether event concerns a watched path.
+func (w *Watcher) handle(event fsnotify.Event) bool {
+	if !event.Has(fsnotify.Create | fsnotify.Write | fsnotify.Rename | fsnotify.Remove) {
+		return false
+	}
+	path := filepath.Clean(event.Name)
+	if w.files[path] {
+		return true
+	}
+	parent := filepath.Dir(path)
+	for dir, recursive := range w.dirs {
+		if parent == dir || (recursive && isWithin(parent, dir)) {
+			if recursive && event.Has(fsnotify.Create) {
+				if info, err := os.Stat(path); err == nil && info.IsDir() {
+					_ = w.addTree(path)
+					return false
+				}
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// isWithin reports whether path is below dir.
+func isWithin(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
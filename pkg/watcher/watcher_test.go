@@ -0,0 +1,171 @@
+package watcher_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/sgaunet/mdtohtml/pkg/watcher"
+)
+
+const testDebounce = 50 * time.Millisecond
+
+// startWatcher runs w in the background and returns a channel receiving every
+// reported batch. The watcher is stopped when the test ends.
+func startWatcher(t *testing.T, w *watcher.Watcher) <-chan []string {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	batches := make(chan []string, 16)
+	done := make(chan error, 1)
+	go func() {
+		done <- w.Run(ctx, func(changed []string) { batches <- changed })
+	}()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Run() error = %v", err)
+		}
+		_ = w.Close()
+	})
+	return batches
+}
+
+// waitFor returns the first batch containing path, failing the test if none
+// arrives in time.
+func waitFor(t *testing.T, batches <-chan []string, path string) []string {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case changed := <-batches:
+			if slices.Contains(changed, path) {
+				return changed
+			}
+		case <-timeout:
+			t.Fatalf("no change reported for %s", path)
+			return nil
+		}
+	}
+}
+
+// expectQuiet fails the test if a batch is reported within a few debounce
+// periods.
+func expectQuiet(t *testing.T, batches <-chan []string) {
+	t.Helper()
+	select {
+	case changed := <-batches:
+		t.Errorf("unexpected change reported: %v", changed)
+	case <-time.After(5 * testDebounce):
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
+
+// TestWatcher_AddFile tests that only the watched file of a directory is
+// reported and that quick successive writes are debounced into one batch.
+func TestWatcher_AddFile(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "style.css")
+	other := filepath.Join(dir, "other.css")
+	writeFile(t, target, "body {}")
+
+	w, err := watcher.New(testDebounce)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if err := w.AddFile(target); err != nil {
+		t.Fatalf("AddFile() error = %v", err)
+	}
+	batches := startWatcher(t, w)
+
+	writeFile(t, other, "p {}")
+	expectQuiet(t, batches)
+
+	for range 3 {
+		writeFile(t, target, "body { color: red; }")
+	}
+	changed := waitFor(t, batches, target)
+	if len(changed) != 1 {
+		t.Errorf("changed = %v, want only %s", changed, target)
+	}
+	expectQuiet(t, batches)
+}
+
+// TestWatcher_AddDir tests directory watching with and without recursion.
+func TestWatcher_AddDir(t *testing.T) {
+	tests := []struct {
+		name      string
+		recursive bool
+	}{
+		{name: "non-recursive", recursive: false},
+		{name: "recursive", recursive: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			w, err := watcher.New(testDebounce)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			if err := w.AddDir(dir, tt.recursive); err != nil {
+				t.Fatalf("AddDir() error = %v", err)
+			}
+			batches := startWatcher(t, w)
+
+			top := filepath.Join(dir, "top.md")
+			writeFile(t, top, "# Top")
+			waitFor(t, batches, top)
+
+			// A directory created after watching started is picked up by
+			// recursive watchers only.
+			sub := filepath.Join(dir, "sub")
+			if err := os.Mkdir(sub, 0750); err != nil {
+				t.Fatalf("Failed to create directory: %v", err)
+			}
+			// The directory itself may be reported by the non-recursive
+			// watcher; only the file inside it matters here.
+			time.Sleep(3 * testDebounce)
+			for len(batches) > 0 {
+				<-batches
+			}
+			nested := filepath.Join(sub, "nested.md")
+			writeFile(t, nested, "# Nested")
+			if tt.recursive {
+				waitFor(t, batches, nested)
+			} else {
+				expectQuiet(t, batches)
+			}
+		})
+	}
+}
+
+// TestWatcher_AddErrors tests that unwatchable paths return ErrWatch.
+func TestWatcher_AddErrors(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing")
+
+	w, err := watcher.New(0)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer func() { _ = w.Close() }()
+
+	if err := w.AddDir(missing, false); !errors.Is(err, watcher.ErrWatch) {
+		t.Errorf("AddDir(non-recursive) error = %v, want %v", err, watcher.ErrWatch)
+	}
+	if err := w.AddDir(missing, true); !errors.Is(err, watcher.ErrWatch) {
+		t.Errorf("AddDir(recursive) error = %v, want %v", err, watcher.ErrWatch)
+	}
+	if err := w.AddFile(filepath.Join(missing, "file.md")); !errors.Is(err, watcher.ErrWatch) {
+		t.Errorf("AddFile() error = %v, want %v", err, watcher.ErrWatch)
+	}
+}